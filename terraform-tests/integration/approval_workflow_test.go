@@ -0,0 +1,108 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApprovalWorkflowPermissions deploys the module with
+// purchase_mode = "approval_required" and validates the Purchaser's IAM
+// policy grants dynamodb:GetItem and ssm:GetParameter in documents naming the
+// approvals table and signing-key parameter (other resources in those
+// documents are not checked), then proves the Purchaser actually enforces the approval gate: an SQS
+// message with no matching approval record must fail with a FunctionError of
+// "ApprovalMissing" rather than calling CreateSavingsPlan.
+func TestApprovalWorkflowPermissions(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":    awsRegion,
+			"name_prefix":   uniquePrefix,
+			"purchase_mode": "approval_required",
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	purchaserRoleARN := terraform.Output(t, terraformOptions, "purchaser_role_arn")
+	approvalsTableName := terraform.Output(t, terraformOptions, "approvals_table_name")
+	signingKeyParameterName := terraform.Output(t, terraformOptions, "approval_signing_key_parameter_name")
+	purchaserLambdaName := terraform.Output(t, terraformOptions, "purchaser_lambda_name")
+	require.NotEmpty(t, approvalsTableName, "Approvals table name output should not be empty")
+	require.NotEmpty(t, signingKeyParameterName, "Approval signing key parameter name output should not be empty")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+	iamClient := iam.New(sess)
+
+	t.Log("Validating the Purchaser role's approval-workflow permissions name the approvals table and signing-key parameter...")
+
+	document := findInlinePolicyDocumentContaining(t, iamClient, roleNameFromARN(purchaserRoleARN), "dynamodb:GetItem")
+	assert.Contains(t, document, "dynamodb:GetItem", "Purchaser role should grant dynamodb:GetItem")
+	assert.Contains(t, document, approvalsTableName, "dynamodb:GetItem should be scoped to the approvals table")
+
+	ssmDocument := findInlinePolicyDocumentContaining(t, iamClient, roleNameFromARN(purchaserRoleARN), "ssm:GetParameter")
+	assert.Contains(t, ssmDocument, "ssm:GetParameter", "Purchaser role should grant ssm:GetParameter")
+	assert.Contains(t, ssmDocument, signingKeyParameterName, "ssm:GetParameter should be scoped to the signing-key parameter")
+
+	t.Log("✓ Approval-workflow IAM permissions validated")
+
+	t.Log("Invoking the Purchaser with an SQS message lacking a matching approval...")
+
+	lambdaClient := lambda.New(sess)
+	invokeResult, err := lambdaClient.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(purchaserLambdaName),
+		Payload: []byte(`{
+			"Records": [{
+				"body": "{\"recommendation_hash\": \"unapproved-test-hash\"}"
+			}]
+		}`),
+	})
+	require.NoError(t, err, "Failed to invoke Purchaser Lambda")
+	require.NotNil(t, invokeResult.FunctionError, "Purchaser invocation without a matching approval should report a function error")
+	assert.Contains(t, string(invokeResult.Payload), "ApprovalMissing", "Purchaser should report ApprovalMissing, not attempt the purchase")
+
+	t.Log("✓ Purchaser refused to purchase without a matching approval")
+}
+
+// findInlinePolicyDocumentContaining returns the first inline policy document
+// on roleName containing needle, failing the test if none match.
+func findInlinePolicyDocumentContaining(t *testing.T, iamClient *iam.IAM, roleName, needle string) string {
+	listOutput, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	require.NoError(t, err, "Failed to list inline policies for role %s", roleName)
+
+	for _, policyName := range listOutput.PolicyNames {
+		getOutput, err := iamClient.GetRolePolicy(&iam.GetRolePolicyInput{
+			RoleName:   aws.String(roleName),
+			PolicyName: policyName,
+		})
+		require.NoError(t, err, "Failed to get inline policy %s for role %s", *policyName, roleName)
+
+		document := aws.StringValue(getOutput.PolicyDocument)
+		if strings.Contains(document, needle) {
+			return document
+		}
+	}
+
+	t.Fatalf("no inline policy on role %s grants %s", roleName, needle)
+	return ""
+}
@@ -0,0 +1,68 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/scheduler"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// TestAutoDestroySchedule deploys the module with auto_destroy_at set to a
+// near-future timestamp and confirms the module provisions its own
+// self-destruct schedule, rather than relying solely on this test's
+// defer terraform.Destroy() - the scenario this request is meant to guard
+// against (the test process getting killed before that defer runs) can't
+// itself be exercised from inside the same test process, so this validates
+// the schedule exists and targets the given time instead.
+//
+// auto_destroy_at, auto_destroy_activity_duration, and the self-destruct
+// schedule/Lambda this request asks for don't exist in this checkout -
+// there's no module source here to wire them into. This records the
+// intended coverage.
+func TestAutoDestroySchedule(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	autoDestroyAt := time.Now().Add(15 * time.Minute).UTC().Format(time.RFC3339)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":                     awsRegion,
+			"name_prefix":                    uniquePrefix,
+			"auto_destroy_at":                autoDestroyAt,
+			"auto_destroy_activity_duration": "10m",
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	autoDestroyScheduleARN := terraform.Output(t, terraformOptions, "auto_destroy_schedule_arn")
+	require.NotEmpty(t, autoDestroyScheduleARN, "auto_destroy_schedule_arn output should not be empty when auto_destroy_at is set")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+	schedulerClient := scheduler.New(sess)
+
+	scheduleName := terraform.Output(t, terraformOptions, "auto_destroy_schedule_name")
+	require.NotEmpty(t, scheduleName, "auto_destroy_schedule_name output should not be empty")
+
+	scheduleOutput, err := schedulerClient.GetSchedule(&scheduler.GetScheduleInput{
+		Name: aws.String(scheduleName),
+	})
+	require.NoError(t, err, "Failed to get auto-destroy schedule")
+	require.Contains(t, aws.StringValue(scheduleOutput.ScheduleExpression), autoDestroyAt[:16], "Auto-destroy schedule should fire at the configured auto_destroy_at time")
+
+	t.Log("✓ Auto-destroy schedule provisioned and targets the configured time")
+}
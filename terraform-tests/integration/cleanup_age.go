@@ -0,0 +1,62 @@
+package test
+
+import (
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// defaultMinAge is how long a resource must have existed before cleanup will
+// touch it, protecting an in-flight test run that shares the AWS account from
+// a concurrent cleanup sweep.
+const defaultMinAge = 2 * time.Hour
+
+// minAgeFromEnv reads CLEANUP_MIN_AGE (a Go duration string, e.g. "30m") and
+// falls back to defaultMinAge when unset or invalid.
+func minAgeFromEnv() time.Duration {
+	raw := os.Getenv("CLEANUP_MIN_AGE")
+	if raw == "" {
+		return defaultMinAge
+	}
+	d, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultMinAge
+	}
+	return d
+}
+
+// oldEnough reports whether createdAt is older than minAge.
+func oldEnough(createdAt time.Time, minAge time.Duration) bool {
+	return time.Since(createdAt) >= minAge
+}
+
+// queueOldEnough reports whether the SQS queue at queueURL is older than
+// minAge, reading CreatedTimestamp via GetQueueAttributes. A queue whose
+// creation time can't be determined is treated as NOT old enough: the whole
+// point of the age gate is protecting an in-flight concurrent test run's
+// brand-new queue, and a transient GetQueueAttributes failure is exactly the
+// case that queue is most likely to hit.
+func queueOldEnough(sqsClient *sqs.SQS, queueURL string, minAge time.Duration) bool {
+	output, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []*string{aws.String(sqs.QueueAttributeNameCreatedTimestamp)},
+	})
+	if err != nil {
+		return false
+	}
+
+	raw, ok := output.Attributes[sqs.QueueAttributeNameCreatedTimestamp]
+	if !ok || raw == nil {
+		return false
+	}
+
+	createdSec, err := strconv.ParseInt(*raw, 10, 64)
+	if err != nil {
+		return false
+	}
+
+	return oldEnough(time.Unix(createdSec, 0), minAge)
+}
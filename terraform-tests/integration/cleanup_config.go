@@ -0,0 +1,147 @@
+package test
+
+import (
+	"os"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// defaultCleanupConfigPath is where TestCleanupAllOrphanedResources looks for
+// operator overrides. It is optional: when absent, defaultCleanupConfig() is used.
+const defaultCleanupConfigPath = "cleanup_config.yaml"
+
+// cleanupRule is a list of regular expressions matched against a resource's
+// name (or ARN, where names aren't unique identifiers on their own).
+type cleanupRule struct {
+	NamesRegex []string `yaml:"names_regex"`
+}
+
+// cleanupSection declares the include/exclude rules for a single resource type.
+// A resource is a cleanup candidate only if it matches at least one include
+// pattern and no exclude pattern.
+type cleanupSection struct {
+	Include cleanupRule `yaml:"include"`
+	Exclude cleanupRule `yaml:"exclude"`
+}
+
+// CleanupConfig is the YAML-configurable shape of cleanup_config.yaml, modeled
+// after cloud-nuke's per-resource-type include/exclude rules.
+type CleanupConfig struct {
+	CloudWatchAlarms      cleanupSection `yaml:"CloudWatchAlarms"`
+	LogGroups             cleanupSection `yaml:"LogGroups"`
+	LambdaFunctions       cleanupSection `yaml:"LambdaFunctions"`
+	EventBridgeRules      cleanupSection `yaml:"EventBridgeRules"`
+	SQSQueues             cleanupSection `yaml:"SQSQueues"`
+	SNSTopics             cleanupSection `yaml:"SNSTopics"`
+	IAMRoles              cleanupSection `yaml:"IAMRoles"`
+	S3Buckets             cleanupSection `yaml:"S3Buckets"`
+	VPCs                  cleanupSection `yaml:"VPCs"`
+	SecretsManagerSecrets cleanupSection `yaml:"SecretsManagerSecrets"`
+	KMSKeys               cleanupSection `yaml:"KMSKeys"`
+	DynamoDBTables        cleanupSection `yaml:"DynamoDBTables"`
+}
+
+// Filter holds the compiled regex lists for a single resource type.
+type Filter struct {
+	Include []*regexp.Regexp
+	Exclude []*regexp.Regexp
+}
+
+// Matches reports whether name satisfies the filter: it must match at least
+// one include pattern and no exclude pattern. A filter with no include
+// patterns matches nothing, so a misconfigured section fails safe rather than
+// matching everything.
+func (f Filter) Matches(name string) bool {
+	matched, _ := f.MatchingRule(name)
+	return matched
+}
+
+// MatchingRule reports whether name satisfies the filter, and if so, the
+// include pattern it matched (for attribution in the cleanup report).
+func (f Filter) MatchingRule(name string) (bool, string) {
+	matchedRule := ""
+	for _, re := range f.Include {
+		if re.MatchString(name) {
+			matchedRule = re.String()
+			break
+		}
+	}
+	if matchedRule == "" {
+		return false, ""
+	}
+
+	for _, re := range f.Exclude {
+		if re.MatchString(name) {
+			return false, ""
+		}
+	}
+	return true, matchedRule
+}
+
+// defaultCleanupConfig reproduces the historical hardcoded prefixes as default
+// include patterns, so behavior is unchanged for operators who don't ship a
+// cleanup_config.yaml.
+func defaultCleanupConfig() CleanupConfig {
+	testPrefixes := cleanupRule{NamesRegex: []string{"^sp-autopilot-test", "^sp-test-"}}
+	logGroupPrefixes := cleanupRule{NamesRegex: []string{"^/aws/lambda/sp-autopilot-test", "^/aws/lambda/sp-test-"}}
+	snsTopicPrefixes := cleanupRule{NamesRegex: []string{"sp-autopilot-test-", "sp-test-"}}
+	kmsAliasPrefixes := cleanupRule{NamesRegex: []string{"^alias/sp-autopilot-test", "^alias/sp-test-"}}
+
+	return CleanupConfig{
+		CloudWatchAlarms:      cleanupSection{Include: testPrefixes},
+		LogGroups:             cleanupSection{Include: logGroupPrefixes},
+		LambdaFunctions:       cleanupSection{Include: testPrefixes},
+		EventBridgeRules:      cleanupSection{Include: testPrefixes},
+		SQSQueues:             cleanupSection{Include: testPrefixes},
+		SNSTopics:             cleanupSection{Include: snsTopicPrefixes},
+		IAMRoles:              cleanupSection{Include: testPrefixes},
+		S3Buckets:             cleanupSection{Include: testPrefixes},
+		VPCs:                  cleanupSection{Include: testPrefixes},
+		SecretsManagerSecrets: cleanupSection{Include: testPrefixes},
+		KMSKeys:               cleanupSection{Include: kmsAliasPrefixes},
+		DynamoDBTables:        cleanupSection{Include: testPrefixes},
+	}
+}
+
+// loadCleanupConfig reads path if it exists, falling back to defaultCleanupConfig
+// when the file is absent. A present-but-invalid file is a hard error: cleanup
+// should not silently fall back to defaults when an operator's rules fail to parse.
+func loadCleanupConfig(path string) (CleanupConfig, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defaultCleanupConfig(), nil
+	}
+	if err != nil {
+		return CleanupConfig{}, err
+	}
+
+	cfg := defaultCleanupConfig()
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return CleanupConfig{}, err
+	}
+	return cfg, nil
+}
+
+// compileFilter compiles a cleanupSection's regex strings into a Filter.
+func compileFilter(section cleanupSection) (Filter, error) {
+	filter := Filter{}
+
+	for _, pattern := range section.Include.NamesRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Filter{}, err
+		}
+		filter.Include = append(filter.Include, re)
+	}
+
+	for _, pattern := range section.Exclude.NamesRegex {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return Filter{}, err
+		}
+		filter.Exclude = append(filter.Exclude, re)
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,371 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/secretsmanager"
+)
+
+// kmsPendingWindowDays is the minimum deletion pending window KMS allows.
+const kmsPendingWindowDays = 7
+
+// discoverVPCs finds test VPCs (matched by their Name tag, falling back to
+// the VPC ID when untagged), requires the sp-autopilot:managed-by=test tag
+// like every other resource type, and deletes each one along with everything
+// that would otherwise block VpcId deletion: instances, ENIs, NAT gateways,
+// internet gateways, subnets, route tables, and non-default security groups.
+//
+// DescribeVpcs doesn't return an ARN, so matching against tagged is done by
+// ARN suffix (vpcIsTagged) rather than by building the full ARN, which would
+// need a separate STS call just to learn the account ID.
+//
+// DescribeVpcs also doesn't return a creation timestamp, so the age gate
+// looks up the VPC's CreateVpc event in CloudTrail instead (vpcOldEnough).
+func discoverVPCs(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
+	t.Log("\n[VPCs]")
+	ec2Client := ec2.New(sess)
+	trailClient := cloudtrail.New(sess)
+
+	output, err := ec2Client.DescribeVpcs(&ec2.DescribeVpcsInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list VPCs: %v", err)
+		return nil
+	}
+
+	var jobs []cleanupJob
+	for _, vpc := range output.Vpcs {
+		name := vpcName(vpc)
+		matched, rule := filter.MatchingRule(name)
+		if !matched || (requireTag && !vpcIsTagged(tagged, *vpc.VpcId)) {
+			continue
+		}
+		if !vpcOldEnough(trailClient, *vpc.VpcId, minAge) {
+			jobs = append(jobs, skippedJob("VPC", name, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
+		}
+
+		vpcID := *vpc.VpcId
+		jobs = append(jobs, cleanupJob{
+			resourceType: "VPC",
+			name:         name,
+			matchedRule:  rule,
+			delete: func() error {
+				return deleteVPCAndDependents(ec2Client, vpcID)
+			},
+		})
+	}
+
+	if len(jobs) == 0 {
+		t.Log("  ✓ No orphaned VPCs found")
+	}
+	return jobs
+}
+
+// vpcIsTagged reports whether vpcID carries the sp-autopilot:managed-by=test
+// tag, matching by ARN suffix since DescribeVpcs doesn't return the VPC's own
+// ARN to look up in tagged directly.
+func vpcIsTagged(tagged map[string]bool, vpcID string) bool {
+	suffix := "vpc/" + vpcID
+	for arn := range tagged {
+		if strings.HasSuffix(arn, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// kmsKeyIsTagged reports whether keyID carries the sp-autopilot:managed-by=test
+// tag, matching by ARN suffix since ListAliases doesn't return the key's own
+// ARN to look up in tagged directly.
+func kmsKeyIsTagged(tagged map[string]bool, keyID string) bool {
+	suffix := "key/" + keyID
+	for arn := range tagged {
+		if strings.HasSuffix(arn, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// vpcOldEnough reports whether vpcID's CreateVpc event in CloudTrail is older
+// than minAge. A VPC whose creation event can't be found (LookupEvents erred,
+// or the event has already aged out of CloudTrail's lookup window) is treated
+// as NOT old enough, so an indeterminate age never lets a concurrent test
+// run's VPC get raced by cleanup.
+func vpcOldEnough(trailClient *cloudtrail.CloudTrail, vpcID string, minAge time.Duration) bool {
+	output, err := trailClient.LookupEvents(&cloudtrail.LookupEventsInput{
+		LookupAttributes: []*cloudtrail.LookupAttribute{
+			{AttributeKey: aws.String("ResourceName"), AttributeValue: aws.String(vpcID)},
+		},
+		MaxResults: aws.Int64(50),
+	})
+	if err != nil {
+		return false
+	}
+
+	for _, event := range output.Events {
+		if event.EventName != nil && *event.EventName == "CreateVpc" && event.EventTime != nil {
+			return oldEnough(*event.EventTime, minAge)
+		}
+	}
+	return false
+}
+
+// vpcName returns the VPC's Name tag, falling back to its ID when untagged.
+func vpcName(vpc *ec2.Vpc) string {
+	for _, tag := range vpc.Tags {
+		if tag.Key != nil && *tag.Key == "Name" {
+			return *tag.Value
+		}
+	}
+	return *vpc.VpcId
+}
+
+// deleteVPCAndDependents removes everything that would block VPC deletion,
+// in dependency order, before deleting the VPC itself.
+func deleteVPCAndDependents(ec2Client *ec2.EC2, vpcID string) error {
+	vpcFilter := []*ec2.Filter{{Name: aws.String("vpc-id"), Values: []*string{&vpcID}}}
+
+	instancesOutput, err := ec2Client.DescribeInstances(&ec2.DescribeInstancesInput{Filters: vpcFilter})
+	if err == nil {
+		var instanceIDs []*string
+		for _, reservation := range instancesOutput.Reservations {
+			for _, instance := range reservation.Instances {
+				instanceIDs = append(instanceIDs, instance.InstanceId)
+			}
+		}
+		if len(instanceIDs) > 0 {
+			_, _ = ec2Client.TerminateInstances(&ec2.TerminateInstancesInput{InstanceIds: instanceIDs})
+		}
+	}
+
+	natGatewaysOutput, err := ec2Client.DescribeNatGateways(&ec2.DescribeNatGatewaysInput{Filter: vpcFilter})
+	if err == nil {
+		for _, natGateway := range natGatewaysOutput.NatGateways {
+			_, _ = ec2Client.DeleteNatGateway(&ec2.DeleteNatGatewayInput{NatGatewayId: natGateway.NatGatewayId})
+		}
+	}
+
+	enisOutput, err := ec2Client.DescribeNetworkInterfaces(&ec2.DescribeNetworkInterfacesInput{Filters: vpcFilter})
+	if err == nil {
+		for _, eni := range enisOutput.NetworkInterfaces {
+			if eni.Attachment != nil {
+				_, _ = ec2Client.DetachNetworkInterface(&ec2.DetachNetworkInterfaceInput{AttachmentId: eni.Attachment.AttachmentId, Force: aws.Bool(true)})
+			}
+			_, _ = ec2Client.DeleteNetworkInterface(&ec2.DeleteNetworkInterfaceInput{NetworkInterfaceId: eni.NetworkInterfaceId})
+		}
+	}
+
+	igwsOutput, err := ec2Client.DescribeInternetGateways(&ec2.DescribeInternetGatewaysInput{
+		Filters: []*ec2.Filter{{Name: aws.String("attachment.vpc-id"), Values: []*string{&vpcID}}},
+	})
+	if err == nil {
+		for _, igw := range igwsOutput.InternetGateways {
+			_, _ = ec2Client.DetachInternetGateway(&ec2.DetachInternetGatewayInput{InternetGatewayId: igw.InternetGatewayId, VpcId: &vpcID})
+			_, _ = ec2Client.DeleteInternetGateway(&ec2.DeleteInternetGatewayInput{InternetGatewayId: igw.InternetGatewayId})
+		}
+	}
+
+	subnetsOutput, err := ec2Client.DescribeSubnets(&ec2.DescribeSubnetsInput{Filters: vpcFilter})
+	if err == nil {
+		for _, subnet := range subnetsOutput.Subnets {
+			_, _ = ec2Client.DeleteSubnet(&ec2.DeleteSubnetInput{SubnetId: subnet.SubnetId})
+		}
+	}
+
+	routeTablesOutput, err := ec2Client.DescribeRouteTables(&ec2.DescribeRouteTablesInput{Filters: vpcFilter})
+	if err == nil {
+		for _, routeTable := range routeTablesOutput.RouteTables {
+			if isMainRouteTable(routeTable) {
+				continue
+			}
+			_, _ = ec2Client.DeleteRouteTable(&ec2.DeleteRouteTableInput{RouteTableId: routeTable.RouteTableId})
+		}
+	}
+
+	securityGroupsOutput, err := ec2Client.DescribeSecurityGroups(&ec2.DescribeSecurityGroupsInput{Filters: vpcFilter})
+	if err == nil {
+		for _, sg := range securityGroupsOutput.SecurityGroups {
+			if sg.GroupName != nil && *sg.GroupName == "default" {
+				continue
+			}
+			_, _ = ec2Client.DeleteSecurityGroup(&ec2.DeleteSecurityGroupInput{GroupId: sg.GroupId})
+		}
+	}
+
+	_, err = ec2Client.DeleteVpc(&ec2.DeleteVpcInput{VpcId: &vpcID})
+	return err
+}
+
+func isMainRouteTable(routeTable *ec2.RouteTable) bool {
+	for _, association := range routeTable.Associations {
+		if association.Main != nil && *association.Main {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverSecretsManagerSecrets finds test secrets and force-deletes them
+// without the usual recovery window, since a resource that matched the test
+// filter is disposable by definition.
+func discoverSecretsManagerSecrets(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
+	t.Log("\n[Secrets Manager Secrets]")
+	secretsClient := secretsmanager.New(sess)
+
+	output, err := secretsClient.ListSecrets(&secretsmanager.ListSecretsInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list Secrets Manager secrets: %v", err)
+		return nil
+	}
+
+	var jobs []cleanupJob
+	for _, secret := range output.SecretList {
+		matched, rule := filter.MatchingRule(*secret.Name)
+		if !matched || !tagMatches(tagged, *secret.ARN, requireTag) {
+			continue
+		}
+		if secret.CreatedDate != nil && !oldEnough(*secret.CreatedDate, minAge) {
+			jobs = append(jobs, skippedJob("SecretsManagerSecret", *secret.Name, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
+		}
+		arn := secret.ARN
+		name := *secret.Name
+		jobs = append(jobs, cleanupJob{
+			resourceType: "SecretsManagerSecret",
+			name:         name,
+			matchedRule:  rule,
+			delete: func() error {
+				_, err := secretsClient.DeleteSecret(&secretsmanager.DeleteSecretInput{
+					SecretId:                   arn,
+					ForceDeleteWithoutRecovery: aws.Bool(true),
+				})
+				return err
+			},
+		})
+	}
+
+	if len(jobs) == 0 {
+		t.Log("  ✓ No orphaned Secrets Manager secrets found")
+	}
+	return jobs
+}
+
+// discoverKMSKeys finds test keys by alias, requires the
+// sp-autopilot:managed-by=test tag like every other resource type (matched by
+// ARN suffix via kmsKeyIsTagged, since ListAliases doesn't return the key's
+// own ARN to look up in tagged directly), and age-gates on the key's
+// CreationDate (a DescribeKey call per candidate, same tradeoff discoverVPCs
+// makes with its per-VPC CloudTrail lookup) before scheduling deletion with
+// the minimum 7-day pending window and removing the alias itself.
+func discoverKMSKeys(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
+	t.Log("\n[KMS Keys]")
+	kmsClient := kms.New(sess)
+
+	output, err := kmsClient.ListAliases(&kms.ListAliasesInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list KMS aliases: %v", err)
+		return nil
+	}
+
+	var jobs []cleanupJob
+	for _, alias := range output.Aliases {
+		if alias.TargetKeyId == nil {
+			continue
+		}
+		matched, rule := filter.MatchingRule(*alias.AliasName)
+		if !matched {
+			continue
+		}
+
+		aliasName := *alias.AliasName
+		keyID := *alias.TargetKeyId
+		if requireTag && !kmsKeyIsTagged(tagged, keyID) {
+			continue
+		}
+
+		describeOutput, err := kmsClient.DescribeKey(&kms.DescribeKeyInput{KeyId: &keyID})
+		if err != nil {
+			t.Logf("  ⚠ Failed to describe KMS key %s: %v", keyID, err)
+			continue
+		}
+		if describeOutput.KeyMetadata.CreationDate != nil && !oldEnough(*describeOutput.KeyMetadata.CreationDate, minAge) {
+			jobs = append(jobs, skippedJob("KMSKey", aliasName, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
+		}
+
+		jobs = append(jobs, cleanupJob{
+			resourceType: "KMSKey",
+			name:         aliasName,
+			matchedRule:  rule,
+			delete: func() error {
+				_, _ = kmsClient.DeleteAlias(&kms.DeleteAliasInput{AliasName: &aliasName})
+				_, err := kmsClient.ScheduleKeyDeletion(&kms.ScheduleKeyDeletionInput{
+					KeyId:               &keyID,
+					PendingWindowInDays: aws.Int64(kmsPendingWindowDays),
+				})
+				return err
+			},
+		})
+	}
+
+	if len(jobs) == 0 {
+		t.Log("  ✓ No orphaned KMS keys found")
+	}
+	return jobs
+}
+
+// discoverDynamoDBTables finds test tables by name and deletes them directly.
+func discoverDynamoDBTables(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
+	t.Log("\n[DynamoDB Tables]")
+	dynamoClient := dynamodb.New(sess)
+
+	output, err := dynamoClient.ListTables(&dynamodb.ListTablesInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list DynamoDB tables: %v", err)
+		return nil
+	}
+
+	var jobs []cleanupJob
+	for _, tableNamePtr := range output.TableNames {
+		tableName := *tableNamePtr
+		matched, rule := filter.MatchingRule(tableName)
+		if !matched {
+			continue
+		}
+
+		describeOutput, err := dynamoClient.DescribeTable(&dynamodb.DescribeTableInput{TableName: &tableName})
+		if err != nil || !tagMatches(tagged, *describeOutput.Table.TableArn, requireTag) {
+			continue
+		}
+		if describeOutput.Table.CreationDateTime != nil && !oldEnough(*describeOutput.Table.CreationDateTime, minAge) {
+			jobs = append(jobs, skippedJob("DynamoDBTable", tableName, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
+		}
+
+		name := tableName
+		jobs = append(jobs, cleanupJob{
+			resourceType: "DynamoDBTable",
+			name:         name,
+			matchedRule:  rule,
+			delete: func() error {
+				_, err := dynamoClient.DeleteTable(&dynamodb.DeleteTableInput{TableName: &name})
+				return err
+			},
+		})
+	}
+
+	if len(jobs) == 0 {
+		t.Log("  ✓ No orphaned DynamoDB tables found")
+	}
+	return jobs
+}
@@ -1,8 +1,11 @@
 package test
 
 import (
+	"fmt"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -20,494 +23,590 @@ import (
 // TestCleanupAllOrphanedResources finds and removes ALL orphaned test resources
 // from previous failed test runs using direct AWS API calls (not Terraform).
 //
-// This test cleans up resources matching the pattern "sp-autopilot-test-*" including:
-//   - CloudWatch Log Groups: /aws/lambda/sp-autopilot-test-*
-//   - CloudWatch Alarms: sp-autopilot-test-*
-//   - Lambda Functions: sp-autopilot-test-*
-//   - EventBridge Rules: sp-autopilot-test-*
-//   - SQS Queues: sp-autopilot-test-*
-//   - SNS Topics & Subscriptions: sp-autopilot-test-*
-//   - IAM Roles: sp-autopilot-test-* (with policy detachment)
-//   - S3 Buckets: sp-autopilot-test-* (with object deletion)
+// Which resources are considered orphaned is governed by cleanup_config.yaml
+// (see cleanup_config.go): each resource type declares include/exclude regex
+// rules, defaulting to the historical "sp-autopilot-test-*"/"sp-test-*" name
+// patterns. This covers:
+//   - CloudWatch Log Groups
+//   - CloudWatch Alarms
+//   - Lambda Functions
+//   - EventBridge Rules
+//   - SQS Queues
+//   - SNS Topics & Subscriptions
+//   - IAM Roles (with policy detachment)
+//   - S3 Buckets (with object deletion)
+//   - VPCs (with dependent instances/ENIs/gateways/subnets/route tables/security groups)
+//   - Secrets Manager Secrets (force-deleted, no recovery window)
+//   - KMS Keys (scheduled deletion with a 7-day pending window, plus aliases)
+//   - DynamoDB Tables
+//
+// Each discover* function only discovers candidates; the actual deletes run
+// through a bounded worker pool (CLEANUP_WORKERS, default 8) with rate-limit
+// backoff (see cleanup_parallel.go), so a large backlog of orphaned resources
+// cleans up in seconds instead of minutes.
+//
+// Set CLEANUP_DRY_RUN=1 to discover candidates and write cleanup-report.json
+// without issuing any Delete* calls. This lets CI run cleanup in report-only
+// mode on PRs and only enforce deletion on the main branch.
 //
 // USAGE:
-//   Automated (CI): Runs automatically after integration tests in GitHub Actions
-//   Manual cleanup: go test -v -run TestCleanupAllOrphanedResources -timeout 10m
+//
+//	Automated (CI): Runs automatically after integration tests in GitHub Actions
+//	Manual cleanup: go test -v -run TestCleanupAllOrphanedResources -timeout 10m
+//	Report only:    CLEANUP_DRY_RUN=1 go test -v -run TestCleanupAllOrphanedResources -timeout 10m
+//	Nightly sweep:  go test -v -run TestCleanupOrphans -timeout 10m (see below)
 //
 // SAFETY:
-//   - Only deletes resources with "sp-autopilot-test-" prefix
-//   - Production resources (sp-autopilot-*) are NOT affected
+//   - Only deletes resources matching an include rule and no exclude rule
+//   - A resource type with no include patterns configured matches nothing
+//   - Also requires the sp-autopilot:managed-by=test tag by default; set
+//     CLEANUP_REQUIRE_TAG=0 to fall back to name-prefix-only matching for
+//     resource types the module doesn't stamp that tag on yet
 //   - Uses || true in CI to continue even if cleanup fails
 func TestCleanupAllOrphanedResources(t *testing.T) {
-	awsRegion := "us-east-1"
+	sweepOrphanedResources(t, "us-east-1", "")
+}
+
+// TestCleanupOrphans is the nightly-sweep entrypoint: CI schedules it on a
+// cron trigger, in addition to the post-test cleanup TestCleanupAllOrphanedResources
+// already does, to catch anything a run left behind without a test to clean
+// up after it (e.g. a worker that crashed before its own deferred cleanup ran).
+//
+// It's the same sweep, scoped with CLEANUP_NAME_PREFIX (e.g.
+// "sp-autopilot-test-20240115-") to a single day's resources when the nightly
+// job wants to sweep one date at a time rather than everything CLEANUP_MIN_AGE
+// considers stale.
+func TestCleanupOrphans(t *testing.T) {
+	sweepOrphanedResources(t, "us-east-1", os.Getenv("CLEANUP_NAME_PREFIX"))
+}
+
+// sweepOrphanedResources discovers and deletes every orphaned resource
+// matching cleanup_config.yaml, optionally restricted to names starting with
+// namePrefix (an empty namePrefix matches everything, as usual).
+func sweepOrphanedResources(t *testing.T, awsRegion string, namePrefix string) {
+	dryRun := os.Getenv("CLEANUP_DRY_RUN") == "1"
+	minAge := minAgeFromEnv()
+	workers := cleanupWorkersFromEnv()
 
 	t.Log("========================================")
 	t.Log("Cleaning Up ALL Orphaned Test Resources")
+	if dryRun {
+		t.Log("(dry run: no resources will be deleted)")
+	}
+	if namePrefix != "" {
+		t.Logf("(restricted to name prefix: %s)", namePrefix)
+	}
 	t.Log("========================================")
 
+	cfg, err := loadCleanupConfig(defaultCleanupConfigPath)
+	if err != nil {
+		t.Fatalf("Failed to load cleanup config: %v", err)
+	}
+
 	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
 	if err != nil {
 		t.Fatalf("Failed to create AWS session: %v", err)
 	}
 
-	// Cleanup all resources matching test patterns
-	cleanupAllCloudWatchAlarms(t, sess)
-	cleanupAllLogGroups(t, sess)
-	cleanupAllLambdaFunctions(t, sess)
-	cleanupAllEventBridgeRules(t, sess)
-	cleanupAllSQSQueues(t, sess)
-	cleanupAllSNSTopics(t, sess)
-	cleanupAllIAMRoles(t, sess)
-	cleanupAllS3Buckets(t, sess)
+	// Restricting to a single run's tag (in addition to the always-required
+	// sp-autopilot:managed-by=test marker) is optional: unset, cleanup
+	// considers every tagged test resource regardless of which run made it.
+	runID := os.Getenv("CLEANUP_RUN_ID")
+
+	// requireTag is normally true, but the module doesn't yet stamp
+	// sp-autopilot:managed-by=test on every resource it provisions itself
+	// (only tests that create resources directly via the AWS SDK call
+	// TagTestResource), so CLEANUP_REQUIRE_TAG=0 lets operators fall back to
+	// the previous name-prefix-only matching instead of cleanup silently
+	// finding nothing for module-created resources.
+	requireTag := requireTagFromEnv()
+
+	tagged, err := taggedResourceARNs(sess, []string{
+		"cloudwatch:alarm",
+		"logs:log-group",
+		"lambda:function",
+		"events:rule",
+		"sns:topic",
+		"iam:role",
+		"s3:bucket",
+		"ec2:vpc",
+		"secretsmanager:secret",
+		"kms:key",
+		"dynamodb:table",
+	}, runID)
+	if err != nil {
+		t.Fatalf("Failed to enumerate tagged resources via resourcegroupstaggingapi: %v", err)
+	}
+
+	// Discovery is sequential (cheap List/Describe calls); only the resulting
+	// delete jobs run concurrently, matching both the configured
+	// include/exclude rules AND the sp-autopilot:managed-by=test tag, and
+	// skipping anything younger than minAge so a concurrent test run isn't raced.
+	var jobs []cleanupJob
+	jobs = append(jobs, discoverCloudWatchAlarms(t, sess, mustCompileFilter(t, cfg.CloudWatchAlarms), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverLogGroups(t, sess, mustCompileFilter(t, cfg.LogGroups), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverLambdaFunctions(t, sess, mustCompileFilter(t, cfg.LambdaFunctions), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverEventBridgeRules(t, sess, mustCompileFilter(t, cfg.EventBridgeRules), tagged, requireTag)...)
+	jobs = append(jobs, discoverSQSQueues(t, sess, mustCompileFilter(t, cfg.SQSQueues), runID, requireTag, minAge)...)
+	jobs = append(jobs, discoverSNSTopics(t, sess, mustCompileFilter(t, cfg.SNSTopics), tagged, requireTag)...)
+	jobs = append(jobs, discoverIAMRoles(t, sess, mustCompileFilter(t, cfg.IAMRoles), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverS3Buckets(t, sess, mustCompileFilter(t, cfg.S3Buckets), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverVPCs(t, sess, mustCompileFilter(t, cfg.VPCs), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverSecretsManagerSecrets(t, sess, mustCompileFilter(t, cfg.SecretsManagerSecrets), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverKMSKeys(t, sess, mustCompileFilter(t, cfg.KMSKeys), tagged, requireTag, minAge)...)
+	jobs = append(jobs, discoverDynamoDBTables(t, sess, mustCompileFilter(t, cfg.DynamoDBTables), tagged, requireTag, minAge)...)
+
+	if namePrefix != "" {
+		jobs = restrictToNamePrefix(jobs, namePrefix)
+	}
+
+	t.Logf("\nFound %d cleanup candidates, deleting with %d workers", len(jobs), workers)
+
+	report := &cleanupReport{DryRun: dryRun}
+	runCleanupJobs(t, report, awsRegion, jobs, workers)
+	report.write(t)
 
 	t.Log("========================================")
 	t.Log("Cleanup Complete")
 	t.Log("========================================")
 }
 
-func cleanupAllCloudWatchAlarms(t *testing.T, sess *session.Session) {
+// restrictToNamePrefix keeps only the jobs whose name, or the last "/"- or
+// ":"-separated segment of it (log group paths, SNS topic ARNs, KMS alias
+// names), starts with prefix, for a nightly sweep that targets a single day's
+// resources (e.g. "sp-autopilot-test-20240115-") instead of everything
+// cleanup_config.yaml matches.
+func restrictToNamePrefix(jobs []cleanupJob, prefix string) []cleanupJob {
+	var kept []cleanupJob
+	for _, job := range jobs {
+		lastSegment := job.name[strings.LastIndexAny(job.name, "/:")+1:]
+		if strings.HasPrefix(job.name, prefix) || strings.HasPrefix(lastSegment, prefix) {
+			kept = append(kept, job)
+		}
+	}
+	return kept
+}
+
+// mustCompileFilter compiles a section's regex patterns, failing the test
+// immediately on an invalid pattern rather than silently skipping a resource type.
+func mustCompileFilter(t *testing.T, section cleanupSection) Filter {
+	filter, err := compileFilter(section)
+	if err != nil {
+		t.Fatalf("Invalid cleanup_config.yaml pattern: %v", err)
+	}
+	return filter
+}
+
+func discoverCloudWatchAlarms(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
 	t.Log("\n[CloudWatch Alarms]")
 	cwClient := cloudwatch.New(sess)
 
-	// Check multiple test prefix patterns
-	alarmPrefixes := []string{
-		"sp-autopilot-test",  // Current prefix
-		"sp-test-",           // Old prefix pattern
+	output, err := cwClient.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list CloudWatch alarms: %v", err)
+		return nil
 	}
 
-	deletedCount := 0
-	for _, prefix := range alarmPrefixes {
-		output, err := cwClient.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{
-			AlarmNamePrefix: aws.String(prefix),
-		})
-		if err != nil {
-			t.Logf("  ⚠ Failed to list CloudWatch alarms with prefix %s: %v", prefix, err)
+	var jobs []cleanupJob
+
+	for _, alarm := range output.MetricAlarms {
+		matched, rule := filter.MatchingRule(*alarm.AlarmName)
+		if !matched || !tagMatches(tagged, *alarm.AlarmArn, requireTag) {
 			continue
 		}
-
-		// Delete metric alarms
-		for _, alarm := range output.MetricAlarms {
-			_, err := cwClient.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{
-				AlarmNames: []*string{alarm.AlarmName},
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete CloudWatch alarm %s: %v", *alarm.AlarmName, err)
-			} else {
-				t.Logf("  ✓ Deleted CloudWatch alarm: %s", *alarm.AlarmName)
-				deletedCount++
-			}
+		if alarm.AlarmConfigurationUpdatedTimestamp != nil && !oldEnough(*alarm.AlarmConfigurationUpdatedTimestamp, minAge) {
+			jobs = append(jobs, skippedJob("CloudWatchAlarm", *alarm.AlarmName, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
 		}
+		name := *alarm.AlarmName
+		jobs = append(jobs, cleanupJob{
+			resourceType: "CloudWatchAlarm",
+			name:         name,
+			matchedRule:  rule,
+			delete: func() error {
+				_, err := cwClient.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{AlarmNames: []*string{&name}})
+				return err
+			},
+		})
+	}
 
-		// Delete composite alarms
-		for _, alarm := range output.CompositeAlarms {
-			_, err := cwClient.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{
-				AlarmNames: []*string{alarm.AlarmName},
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete composite alarm %s: %v", *alarm.AlarmName, err)
-			} else {
-				t.Logf("  ✓ Deleted composite alarm: %s", *alarm.AlarmName)
-				deletedCount++
-			}
+	for _, alarm := range output.CompositeAlarms {
+		matched, rule := filter.MatchingRule(*alarm.AlarmName)
+		if !matched || !tagMatches(tagged, *alarm.AlarmArn, requireTag) {
+			continue
+		}
+		if alarm.AlarmConfigurationUpdatedTimestamp != nil && !oldEnough(*alarm.AlarmConfigurationUpdatedTimestamp, minAge) {
+			jobs = append(jobs, skippedJob("CloudWatchCompositeAlarm", *alarm.AlarmName, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
 		}
+		name := *alarm.AlarmName
+		jobs = append(jobs, cleanupJob{
+			resourceType: "CloudWatchCompositeAlarm",
+			name:         name,
+			matchedRule:  rule,
+			delete: func() error {
+				_, err := cwClient.DeleteAlarms(&cloudwatch.DeleteAlarmsInput{AlarmNames: []*string{&name}})
+				return err
+			},
+		})
 	}
 
-	if deletedCount == 0 {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned CloudWatch alarms found")
 	}
+	return jobs
 }
 
-func cleanupAllLogGroups(t *testing.T, sess *session.Session) {
+func discoverLogGroups(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
 	t.Log("\n[CloudWatch Log Groups]")
 	cwlClient := cloudwatchlogs.New(sess)
 
-	// Check multiple test prefix patterns
-	logGroupPrefixes := []string{
-		"/aws/lambda/sp-autopilot-test",  // Current prefix
-		"/aws/lambda/sp-test-",           // Old prefix pattern
+	output, err := cwlClient.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list log groups: %v", err)
+		return nil
 	}
 
-	deletedCount := 0
-	for _, prefix := range logGroupPrefixes {
-		output, err := cwlClient.DescribeLogGroups(&cloudwatchlogs.DescribeLogGroupsInput{
-			LogGroupNamePrefix: aws.String(prefix),
-		})
-		if err != nil {
-			t.Logf("  ⚠ Failed to list log groups with prefix %s: %v", prefix, err)
+	var jobs []cleanupJob
+	for _, logGroup := range output.LogGroups {
+		matched, rule := filter.MatchingRule(*logGroup.LogGroupName)
+		if !matched || !tagMatches(tagged, *logGroup.Arn, requireTag) {
 			continue
 		}
-
-		for _, logGroup := range output.LogGroups {
-			_, err := cwlClient.DeleteLogGroup(&cloudwatchlogs.DeleteLogGroupInput{
-				LogGroupName: logGroup.LogGroupName,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete log group %s: %v", *logGroup.LogGroupName, err)
-			} else {
-				t.Logf("  ✓ Deleted log group: %s", *logGroup.LogGroupName)
-				deletedCount++
+		if logGroup.CreationTime != nil {
+			createdAt := time.Unix(0, *logGroup.CreationTime*int64(time.Millisecond))
+			if !oldEnough(createdAt, minAge) {
+				jobs = append(jobs, skippedJob("LogGroup", *logGroup.LogGroupName, rule, fmt.Sprintf("younger than %s", minAge)))
+				continue
 			}
 		}
+		name := *logGroup.LogGroupName
+		jobs = append(jobs, cleanupJob{
+			resourceType: "LogGroup",
+			name:         name,
+			matchedRule:  rule,
+			delete: func() error {
+				_, err := cwlClient.DeleteLogGroup(&cloudwatchlogs.DeleteLogGroupInput{LogGroupName: &name})
+				return err
+			},
+		})
 	}
 
-	if deletedCount == 0 {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned log groups found")
 	}
+	return jobs
 }
 
-func cleanupAllLambdaFunctions(t *testing.T, sess *session.Session) {
+func discoverLambdaFunctions(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
 	t.Log("\n[Lambda Functions]")
 	lambdaClient := lambda.New(sess)
 
-	// List all Lambda functions
 	output, err := lambdaClient.ListFunctions(&lambda.ListFunctionsInput{})
 	if err != nil {
 		t.Logf("  ⚠ Failed to list Lambda functions: %v", err)
-		return
-	}
-
-	deleted := false
-	// Check multiple test prefix patterns
-	testPrefixes := []string{
-		"sp-autopilot-test-",  // Current prefix
-		"sp-test-",            // Old prefix pattern
+		return nil
 	}
 
+	var jobs []cleanupJob
 	for _, function := range output.Functions {
-		// Only delete functions matching test patterns
-		isTestFunction := false
-		for _, prefix := range testPrefixes {
-			if strings.HasPrefix(*function.FunctionName, prefix) {
-				isTestFunction = true
-				break
-			}
+		matched, rule := filter.MatchingRule(*function.FunctionName)
+		if !matched || !tagMatches(tagged, *function.FunctionArn, requireTag) {
+			continue
 		}
-
-		if isTestFunction {
-			_, err := lambdaClient.DeleteFunction(&lambda.DeleteFunctionInput{
-				FunctionName: function.FunctionName,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete Lambda function %s: %v", *function.FunctionName, err)
-			} else {
-				t.Logf("  ✓ Deleted Lambda function: %s", *function.FunctionName)
-				deleted = true
+		if function.LastModified != nil {
+			if lastModified, err := time.Parse("2006-01-02T15:04:05.000-0700", *function.LastModified); err == nil && !oldEnough(lastModified, minAge) {
+				jobs = append(jobs, skippedJob("LambdaFunction", *function.FunctionName, rule, fmt.Sprintf("younger than %s", minAge)))
+				continue
 			}
 		}
+		name := *function.FunctionName
+		jobs = append(jobs, cleanupJob{
+			resourceType: "LambdaFunction",
+			name:         name,
+			matchedRule:  rule,
+			delete: func() error {
+				_, err := lambdaClient.DeleteFunction(&lambda.DeleteFunctionInput{FunctionName: &name})
+				return err
+			},
+		})
 	}
 
-	if !deleted {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned Lambda functions found")
 	}
+	return jobs
 }
 
-func cleanupAllEventBridgeRules(t *testing.T, sess *session.Session) {
+// discoverEventBridgeRules is not age-gated: ListRules/DescribeRule expose no
+// creation timestamp, and resolving one via CloudTrail lookups is not worth
+// the added API calls for a cleanup sweep. Name/tag filtering still applies.
+func discoverEventBridgeRules(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool) []cleanupJob {
 	t.Log("\n[EventBridge Rules]")
 	eventsClient := cloudwatchevents.New(sess)
 
-	// Check multiple test prefix patterns
-	rulePrefixes := []string{
-		"sp-autopilot-test",  // Current prefix
-		"sp-test-",           // Old prefix pattern
+	output, err := eventsClient.ListRules(&cloudwatchevents.ListRulesInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list EventBridge rules: %v", err)
+		return nil
 	}
 
-	deletedCount := 0
-	for _, prefix := range rulePrefixes {
-		output, err := eventsClient.ListRules(&cloudwatchevents.ListRulesInput{
-			NamePrefix: aws.String(prefix),
-		})
-		if err != nil {
-			t.Logf("  ⚠ Failed to list EventBridge rules with prefix %s: %v", prefix, err)
+	var jobs []cleanupJob
+	for _, rule := range output.Rules {
+		matched, matchedRule := filter.MatchingRule(*rule.Name)
+		if !matched || !tagMatches(tagged, *rule.Arn, requireTag) {
 			continue
 		}
+		name := *rule.Name
+		jobs = append(jobs, cleanupJob{
+			resourceType: "EventBridgeRule",
+			name:         name,
+			matchedRule:  matchedRule,
+			delete: func() error {
+				// First, remove all targets from the rule
+				targetsOutput, err := eventsClient.ListTargetsByRule(&cloudwatchevents.ListTargetsByRuleInput{
+					Rule: &name,
+				})
+				if err == nil && len(targetsOutput.Targets) > 0 {
+					targetIDs := make([]*string, len(targetsOutput.Targets))
+					for i, target := range targetsOutput.Targets {
+						targetIDs[i] = target.Id
+					}
+					_, _ = eventsClient.RemoveTargets(&cloudwatchevents.RemoveTargetsInput{
+						Rule: &name,
+						Ids:  targetIDs,
+					})
+				}
 
-		for _, rule := range output.Rules {
-		// First, remove all targets from the rule
-		targetsOutput, err := eventsClient.ListTargetsByRule(&cloudwatchevents.ListTargetsByRuleInput{
-			Rule: rule.Name,
+				_, err = eventsClient.DeleteRule(&cloudwatchevents.DeleteRuleInput{Name: &name})
+				return err
+			},
 		})
-		if err == nil && len(targetsOutput.Targets) > 0 {
-			targetIDs := make([]*string, len(targetsOutput.Targets))
-			for i, target := range targetsOutput.Targets {
-				targetIDs[i] = target.Id
-			}
-			_, err = eventsClient.RemoveTargets(&cloudwatchevents.RemoveTargetsInput{
-				Rule: rule.Name,
-				Ids:  targetIDs,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to remove targets from rule %s: %v", *rule.Name, err)
-			}
-		}
-
-			// Now delete the rule
-			_, err = eventsClient.DeleteRule(&cloudwatchevents.DeleteRuleInput{
-				Name: rule.Name,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete EventBridge rule %s: %v", *rule.Name, err)
-			} else {
-				t.Logf("  ✓ Deleted EventBridge rule: %s", *rule.Name)
-				deletedCount++
-			}
-		}
 	}
 
-	if deletedCount == 0 {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned EventBridge rules found")
 	}
+	return jobs
 }
 
-func cleanupAllSQSQueues(t *testing.T, sess *session.Session) {
+func discoverSQSQueues(t *testing.T, sess *session.Session, filter Filter, runID string, requireTag bool, minAge time.Duration) []cleanupJob {
 	t.Log("\n[SQS Queues]")
 	sqsClient := sqs.New(sess)
 
-	// List all SQS queues with any test-related prefix
-	prefixes := []string{
-		"sp-autopilot-test",  // Current prefix
-		"sp-test-",           // Old prefix pattern
+	output, err := sqsClient.ListQueues(&sqs.ListQueuesInput{})
+	if err != nil {
+		t.Logf("  ⚠ Failed to list SQS queues: %v", err)
+		return nil
 	}
 
-	deletedCount := 0
-	for _, prefix := range prefixes {
-		output, err := sqsClient.ListQueues(&sqs.ListQueuesInput{
-			QueueNamePrefix: aws.String(prefix),
-		})
-		if err != nil {
-			t.Logf("  ⚠ Failed to list SQS queues with prefix %s: %v", prefix, err)
+	var jobs []cleanupJob
+	for _, queueURL := range output.QueueUrls {
+		queueName := queueNameFromURL(*queueURL)
+		matched, rule := filter.MatchingRule(queueName)
+		if !matched || (requireTag && !sqsQueueIsTagged(sqsClient, *queueURL, runID)) {
 			continue
 		}
-
-		for _, queueURL := range output.QueueUrls {
-			_, err := sqsClient.DeleteQueue(&sqs.DeleteQueueInput{
-				QueueUrl: queueURL,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete SQS queue %s: %v", *queueURL, err)
-			} else {
-				t.Logf("  ✓ Deleted SQS queue: %s", *queueURL)
-				deletedCount++
-			}
+		if !queueOldEnough(sqsClient, *queueURL, minAge) {
+			jobs = append(jobs, skippedJob("SQSQueue", queueName, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
 		}
+		url := *queueURL
+		jobs = append(jobs, cleanupJob{
+			resourceType: "SQSQueue",
+			name:         queueName,
+			matchedRule:  rule,
+			delete: func() error {
+				_, err := sqsClient.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: &url})
+				return err
+			},
+		})
 	}
 
-	if deletedCount == 0 {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned SQS queues found")
 	}
+	return jobs
+}
+
+// queueNameFromURL extracts the queue name from an SQS queue URL, since
+// ListQueues only returns URLs and filters match against resource names.
+func queueNameFromURL(queueURL string) string {
+	idx := len(queueURL) - 1
+	for idx >= 0 && queueURL[idx] != '/' {
+		idx--
+	}
+	return queueURL[idx+1:]
 }
 
-func cleanupAllSNSTopics(t *testing.T, sess *session.Session) {
+// discoverSNSTopics is not age-gated: ListTopics exposes no creation
+// timestamp. Name/tag filtering still applies.
+func discoverSNSTopics(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool) []cleanupJob {
 	t.Log("\n[SNS Topics & Subscriptions]")
 	snsClient := sns.New(sess)
 
-	// List all SNS topics
 	output, err := snsClient.ListTopics(&sns.ListTopicsInput{})
 	if err != nil {
 		t.Logf("  ⚠ Failed to list SNS topics: %v", err)
-		return
-	}
-
-	deleted := false
-	// Check multiple test prefix patterns
-	testPrefixes := []string{
-		"sp-autopilot-test-",  // Current prefix
-		"sp-test-",            // Old prefix pattern
+		return nil
 	}
 
+	var jobs []cleanupJob
 	for _, topic := range output.Topics {
-		// Only delete topics matching test patterns
-		isTestTopic := false
-		for _, prefix := range testPrefixes {
-			if strings.Contains(*topic.TopicArn, prefix) {
-				isTestTopic = true
-				break
-			}
+		matched, rule := filter.MatchingRule(*topic.TopicArn)
+		if !matched || !tagMatches(tagged, *topic.TopicArn, requireTag) {
+			continue
 		}
-
-		if isTestTopic {
-			// First, delete all subscriptions for this topic
-			subsOutput, err := snsClient.ListSubscriptionsByTopic(&sns.ListSubscriptionsByTopicInput{
-				TopicArn: topic.TopicArn,
-			})
-			if err == nil {
-				for _, sub := range subsOutput.Subscriptions {
-					_, _ = snsClient.Unsubscribe(&sns.UnsubscribeInput{
-						SubscriptionArn: sub.SubscriptionArn,
-					})
-					t.Logf("  ✓ Deleted subscription: %s", *sub.SubscriptionArn)
+		topicArn := *topic.TopicArn
+		jobs = append(jobs, cleanupJob{
+			resourceType: "SNSTopic",
+			name:         topicArn,
+			matchedRule:  rule,
+			delete: func() error {
+				subsOutput, err := snsClient.ListSubscriptionsByTopic(&sns.ListSubscriptionsByTopicInput{
+					TopicArn: &topicArn,
+				})
+				if err == nil {
+					for _, sub := range subsOutput.Subscriptions {
+						_, _ = snsClient.Unsubscribe(&sns.UnsubscribeInput{SubscriptionArn: sub.SubscriptionArn})
+					}
 				}
-			}
 
-			// Now delete the topic
-			_, err = snsClient.DeleteTopic(&sns.DeleteTopicInput{
-				TopicArn: topic.TopicArn,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete SNS topic %s: %v", *topic.TopicArn, err)
-			} else {
-				t.Logf("  ✓ Deleted SNS topic: %s", *topic.TopicArn)
-				deleted = true
-			}
-		}
+				_, err = snsClient.DeleteTopic(&sns.DeleteTopicInput{TopicArn: &topicArn})
+				return err
+			},
+		})
 	}
 
-	if !deleted {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned SNS topics/subscriptions found")
 	}
+	return jobs
 }
 
-func cleanupAllIAMRoles(t *testing.T, sess *session.Session) {
+func discoverIAMRoles(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
 	t.Log("\n[IAM Roles]")
 	iamClient := iam.New(sess)
 
-	// List all IAM roles
 	output, err := iamClient.ListRoles(&iam.ListRolesInput{
 		PathPrefix: aws.String("/"),
 	})
 	if err != nil {
 		t.Logf("  ⚠ Failed to list IAM roles: %v", err)
-		return
-	}
-
-	deleted := false
-	// Check multiple test prefix patterns
-	testPrefixes := []string{
-		"sp-autopilot-test-",  // Current prefix
-		"sp-test-",            // Old prefix pattern
+		return nil
 	}
 
+	var jobs []cleanupJob
 	for _, role := range output.Roles {
-		// Only delete roles matching test patterns
-		isTestRole := false
-		for _, prefix := range testPrefixes {
-			if strings.HasPrefix(*role.RoleName, prefix) {
-				isTestRole = true
-				break
-			}
+		matched, rule := filter.MatchingRule(*role.RoleName)
+		if !matched || !tagMatches(tagged, *role.Arn, requireTag) {
+			continue
 		}
-
-		if isTestRole {
-			// First, detach all managed policies
-			policiesOutput, err := iamClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
-				RoleName: role.RoleName,
-			})
-			if err == nil {
-				for _, policy := range policiesOutput.AttachedPolicies {
-					_, _ = iamClient.DetachRolePolicy(&iam.DetachRolePolicyInput{
-						RoleName:  role.RoleName,
-						PolicyArn: policy.PolicyArn,
-					})
+		if role.CreateDate != nil && !oldEnough(*role.CreateDate, minAge) {
+			jobs = append(jobs, skippedJob("IAMRole", *role.RoleName, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
+		}
+		roleName := *role.RoleName
+		jobs = append(jobs, cleanupJob{
+			resourceType: "IAMRole",
+			name:         roleName,
+			matchedRule:  rule,
+			delete: func() error {
+				policiesOutput, err := iamClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{
+					RoleName: &roleName,
+				})
+				if err == nil {
+					for _, policy := range policiesOutput.AttachedPolicies {
+						_, _ = iamClient.DetachRolePolicy(&iam.DetachRolePolicyInput{
+							RoleName:  &roleName,
+							PolicyArn: policy.PolicyArn,
+						})
+					}
 				}
-			}
 
-			// Delete inline policies
-			inlinePoliciesOutput, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{
-				RoleName: role.RoleName,
-			})
-			if err == nil {
-				for _, policyName := range inlinePoliciesOutput.PolicyNames {
-					_, _ = iamClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
-						RoleName:   role.RoleName,
-						PolicyName: policyName,
-					})
+				inlinePoliciesOutput, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{
+					RoleName: &roleName,
+				})
+				if err == nil {
+					for _, policyName := range inlinePoliciesOutput.PolicyNames {
+						_, _ = iamClient.DeleteRolePolicy(&iam.DeleteRolePolicyInput{
+							RoleName:   &roleName,
+							PolicyName: policyName,
+						})
+					}
 				}
-			}
 
-			// Now delete the role
-			_, err = iamClient.DeleteRole(&iam.DeleteRoleInput{
-				RoleName: role.RoleName,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete IAM role %s: %v", *role.RoleName, err)
-			} else {
-				t.Logf("  ✓ Deleted IAM role: %s", *role.RoleName)
-				deleted = true
-			}
-		}
+				_, err = iamClient.DeleteRole(&iam.DeleteRoleInput{RoleName: &roleName})
+				return err
+			},
+		})
 	}
 
-	if !deleted {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned IAM roles found")
 	}
+	return jobs
 }
 
-func cleanupAllS3Buckets(t *testing.T, sess *session.Session) {
+func discoverS3Buckets(t *testing.T, sess *session.Session, filter Filter, tagged map[string]bool, requireTag bool, minAge time.Duration) []cleanupJob {
 	t.Log("\n[S3 Buckets]")
 	s3Client := s3.New(sess)
 
-	// List all S3 buckets
 	output, err := s3Client.ListBuckets(&s3.ListBucketsInput{})
 	if err != nil {
 		t.Logf("  ⚠ Failed to list S3 buckets: %v", err)
-		return
-	}
-
-	deleted := false
-	// Check multiple test prefix patterns
-	testPrefixes := []string{
-		"sp-autopilot-test-",  // Current prefix
-		"sp-test-",            // Old prefix pattern
+		return nil
 	}
 
+	var jobs []cleanupJob
 	for _, bucket := range output.Buckets {
-		// Only delete buckets matching test patterns
-		isTestBucket := false
-		for _, prefix := range testPrefixes {
-			if strings.HasPrefix(*bucket.Name, prefix) {
-				isTestBucket = true
-				break
-			}
+		matched, rule := filter.MatchingRule(*bucket.Name)
+		if !matched || !tagMatches(tagged, fmt.Sprintf("arn:aws:s3:::%s", *bucket.Name), requireTag) {
+			continue
 		}
-
-		if isTestBucket {
-			// First, delete all objects in the bucket
-			listObjectsOutput, err := s3Client.ListObjectsV2(&s3.ListObjectsV2Input{
-				Bucket: bucket.Name,
-			})
-			if err == nil {
-				for _, object := range listObjectsOutput.Contents {
-					_, _ = s3Client.DeleteObject(&s3.DeleteObjectInput{
-						Bucket: bucket.Name,
-						Key:    object.Key,
-					})
+		if bucket.CreationDate != nil && !oldEnough(*bucket.CreationDate, minAge) {
+			jobs = append(jobs, skippedJob("S3Bucket", *bucket.Name, rule, fmt.Sprintf("younger than %s", minAge)))
+			continue
+		}
+		bucketName := *bucket.Name
+		jobs = append(jobs, cleanupJob{
+			resourceType: "S3Bucket",
+			name:         bucketName,
+			matchedRule:  rule,
+			delete: func() error {
+				listObjectsOutput, err := s3Client.ListObjectsV2(&s3.ListObjectsV2Input{Bucket: &bucketName})
+				if err == nil {
+					for _, object := range listObjectsOutput.Contents {
+						_, _ = s3Client.DeleteObject(&s3.DeleteObjectInput{Bucket: &bucketName, Key: object.Key})
+					}
 				}
-			}
 
-			// Delete all object versions (if versioning enabled)
-			listVersionsOutput, err := s3Client.ListObjectVersions(&s3.ListObjectVersionsInput{
-				Bucket: bucket.Name,
-			})
-			if err == nil {
-				for _, version := range listVersionsOutput.Versions {
-					_, _ = s3Client.DeleteObject(&s3.DeleteObjectInput{
-						Bucket:    bucket.Name,
-						Key:       version.Key,
-						VersionId: version.VersionId,
-					})
+				listVersionsOutput, err := s3Client.ListObjectVersions(&s3.ListObjectVersionsInput{Bucket: &bucketName})
+				if err == nil {
+					for _, version := range listVersionsOutput.Versions {
+						_, _ = s3Client.DeleteObject(&s3.DeleteObjectInput{
+							Bucket:    &bucketName,
+							Key:       version.Key,
+							VersionId: version.VersionId,
+						})
+					}
+					for _, marker := range listVersionsOutput.DeleteMarkers {
+						_, _ = s3Client.DeleteObject(&s3.DeleteObjectInput{
+							Bucket:    &bucketName,
+							Key:       marker.Key,
+							VersionId: marker.VersionId,
+						})
+					}
 				}
-				for _, marker := range listVersionsOutput.DeleteMarkers {
-					_, _ = s3Client.DeleteObject(&s3.DeleteObjectInput{
-						Bucket:    bucket.Name,
-						Key:       marker.Key,
-						VersionId: marker.VersionId,
-					})
-				}
-			}
 
-			// Now delete the bucket
-			_, err = s3Client.DeleteBucket(&s3.DeleteBucketInput{
-				Bucket: bucket.Name,
-			})
-			if err != nil {
-				t.Logf("  ⚠ Failed to delete S3 bucket %s: %v", *bucket.Name, err)
-			} else {
-				t.Logf("  ✓ Deleted S3 bucket: %s", *bucket.Name)
-				deleted = true
-			}
-		}
+				_, err = s3Client.DeleteBucket(&s3.DeleteBucketInput{Bucket: &bucketName})
+				return err
+			},
+		})
 	}
 
-	if !deleted {
+	if len(jobs) == 0 {
 		t.Log("  ✓ No orphaned S3 buckets found")
 	}
+	return jobs
 }
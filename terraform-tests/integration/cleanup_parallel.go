@@ -0,0 +1,119 @@
+package test
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultCleanupWorkers is how many deletions run concurrently by default;
+// override with the CLEANUP_WORKERS env var.
+const defaultCleanupWorkers = 8
+
+// cleanupJob is one resource a discover* pass decided to delete.
+// The actual delete call is deferred until runCleanupJobs drains the pool, so
+// discovery (sequential, cheap List/Describe calls) and deletion (parallel,
+// rate-limited) stay decoupled.
+//
+// A job can also be age-gated rather than deleted: skipped/skipReason carry
+// that outcome through to the report instead of delete, so
+// cleanup-report.json accounts for every matching resource the age gate
+// protected, not just the ones it actually removed.
+type cleanupJob struct {
+	resourceType string
+	name         string
+	matchedRule  string
+	skipped      bool
+	skipReason   string
+	delete       func() error
+}
+
+// skippedJob builds a cleanupJob for a resource that matched the filter and
+// tag but isn't old enough to touch yet.
+func skippedJob(resourceType, name, matchedRule, reason string) cleanupJob {
+	return cleanupJob{
+		resourceType: resourceType,
+		name:         name,
+		matchedRule:  matchedRule,
+		skipped:      true,
+		skipReason:   reason,
+	}
+}
+
+func cleanupWorkersFromEnv() int {
+	raw := os.Getenv("CLEANUP_WORKERS")
+	if raw == "" {
+		return defaultCleanupWorkers
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return defaultCleanupWorkers
+	}
+	return n
+}
+
+// runCleanupJobs drains jobs through a bounded worker pool, retrying each
+// delete on AWS rate-limit errors with exponential backoff, and recording the
+// outcome of every job in report. It never returns an error itself: a failed
+// job is recorded as "failed" in the report rather than aborting the run.
+func runCleanupJobs(t *testing.T, report *cleanupReport, region string, jobs []cleanupJob, workers int) {
+	g := new(errgroup.Group)
+	g.SetLimit(workers)
+
+	for _, job := range jobs {
+		job := job
+		g.Go(func() error {
+			if job.skipped {
+				report.recordSkipped(job.resourceType, job.name, region, job.matchedRule, job.skipReason)
+				t.Logf("  ⏭ Skipped %s: %s (%s)", job.resourceType, job.name, job.skipReason)
+				return nil
+			}
+			report.record(job.resourceType, job.name, region, job.matchedRule, func() error {
+				return withRetry(job.delete)
+			})
+			t.Logf("  ✓ Processed %s: %s", job.resourceType, job.name)
+			return nil
+		})
+	}
+
+	_ = g.Wait()
+}
+
+// withRetry retries fn with exponential backoff when it fails with an AWS
+// throttling error, up to 5 attempts. Any other error is returned immediately.
+func withRetry(fn func() error) error {
+	const maxAttempts = 5
+	backoff := 200 * time.Millisecond
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		err = fn()
+		if err == nil || !isThrottlingError(err) {
+			return err
+		}
+		if attempt == maxAttempts {
+			break
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+	return err
+}
+
+func isThrottlingError(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	code := awsErr.Code()
+	return code == "ThrottlingException" ||
+		code == "Throttling" ||
+		code == "RequestLimitExceeded" ||
+		code == "TooManyRequestsException" ||
+		strings.Contains(code, "Throttl")
+}
@@ -0,0 +1,130 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// cleanupReportPath is where TestCleanupAllOrphanedResources writes its
+// machine-readable summary after every run (dry-run or not).
+const cleanupReportPath = "cleanup-report.json"
+
+// cleanupAction is the outcome recorded for a single resource in the report.
+type cleanupAction string
+
+const (
+	actionDeleted cleanupAction = "deleted"
+	actionSkipped cleanupAction = "skipped"
+	actionFailed  cleanupAction = "failed"
+	actionDryRun  cleanupAction = "dry-run"
+)
+
+// cleanupReportEntry is one row of cleanup-report.json.
+type cleanupReportEntry struct {
+	Type        string        `json:"type"`
+	Name        string        `json:"name"`
+	Region      string        `json:"region"`
+	MatchedRule string        `json:"matched_rule"`
+	Action      cleanupAction `json:"action"`
+	Reason      string        `json:"reason,omitempty"`
+	Error       string        `json:"error,omitempty"`
+	DurationMS  int64         `json:"duration_ms"`
+}
+
+// cleanupReport accumulates entries across all discover* jobs in a single
+// TestCleanupAllOrphanedResources run. Jobs are deleted concurrently by a
+// worker pool (see cleanup_parallel.go), so record is safe to call from
+// multiple goroutines.
+type cleanupReport struct {
+	DryRun bool `json:"dry_run"`
+
+	mu      sync.Mutex
+	Entries []cleanupReportEntry `json:"entries"`
+}
+
+// record runs delete (skipped in dry-run mode) and appends the outcome to the
+// report. resourceType/name/region/matchedRule describe the candidate being
+// acted on; delete performs the actual AWS API deletion call.
+func (r *cleanupReport) record(resourceType, name, region, matchedRule string, delete func() error) {
+	start := time.Now()
+
+	if r.DryRun {
+		r.append(cleanupReportEntry{
+			Type:        resourceType,
+			Name:        name,
+			Region:      region,
+			MatchedRule: matchedRule,
+			Action:      actionDryRun,
+			DurationMS:  time.Since(start).Milliseconds(),
+		})
+		return
+	}
+
+	entry := cleanupReportEntry{
+		Type:        resourceType,
+		Name:        name,
+		Region:      region,
+		MatchedRule: matchedRule,
+	}
+
+	if err := delete(); err != nil {
+		entry.Action = actionFailed
+		entry.Error = err.Error()
+	} else {
+		entry.Action = actionDeleted
+	}
+	entry.DurationMS = time.Since(start).Milliseconds()
+
+	r.append(entry)
+}
+
+// recordSkipped appends a "skipped" entry for a candidate the age gate
+// decided not to touch yet, so cleanup-report.json accounts for it alongside
+// everything actually deleted or dry-run'd.
+func (r *cleanupReport) recordSkipped(resourceType, name, region, matchedRule, reason string) {
+	r.append(cleanupReportEntry{
+		Type:        resourceType,
+		Name:        name,
+		Region:      region,
+		MatchedRule: matchedRule,
+		Action:      actionSkipped,
+		Reason:      reason,
+	})
+}
+
+func (r *cleanupReport) append(entry cleanupReportEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Entries = append(r.Entries, entry)
+}
+
+// write saves the report as JSON to cleanupReportPath and prints a summary
+// table to stdout.
+func (r *cleanupReport) write(t *testing.T) {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		t.Logf("  ⚠ Failed to marshal cleanup report: %v", err)
+		return
+	}
+	if err := os.WriteFile(cleanupReportPath, data, 0644); err != nil {
+		t.Logf("  ⚠ Failed to write %s: %v", cleanupReportPath, err)
+	}
+
+	t.Log("\n[Cleanup Report]")
+	t.Logf("  %-20s %-45s %-10s %-10s", "TYPE", "NAME", "ACTION", "DURATION")
+	for _, e := range r.Entries {
+		t.Logf("  %-20s %-45s %-10s %dms", e.Type, truncate(e.Name, 45), e.Action, e.DurationMS)
+	}
+	fmt.Printf("Cleanup report written to %s (%d entries)\n", cleanupReportPath, len(r.Entries))
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max-3] + "..."
+}
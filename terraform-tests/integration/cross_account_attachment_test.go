@@ -0,0 +1,132 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crossAccountLinkedProfileEnvVar names the AWS CLI profile this test uses to
+// stand in for a linked account. It's a real second set of credentials, not
+// just a second role in this account, so the test is skipped cleanly when
+// the operator hasn't configured one.
+const crossAccountLinkedProfileEnvVar = "SP_AUTOPILOT_LINKED_ACCOUNT_PROFILE"
+
+// TestCrossAccountPurchaserAttachment deploys ./fixtures/basic with
+// linked_account_ids set to the account behind a real second AWS profile
+// standing in for a linked account (the apply itself runs with the default
+// credentials; the second profile is only used to read that account's ID and
+// its CloudTrail), enqueues a dry-run cross-account
+// purchase carrying a target_account_id, and asserts the resulting
+// AssumeRole call and downstream savingsplans API call show up in the linked
+// account's CloudTrail.
+//
+// The attachment submodule (principals/resources/tags/computed ARN), the
+// target_account_id field on purchase-intent messages, and the Purchaser's
+// assume-then-purchase code path don't exist in this checkout - there's no
+// module source here to wire them into. This records the intended coverage.
+func TestCrossAccountPurchaserAttachment(t *testing.T) {
+	requireApplyTests(t)
+
+	linkedProfile := os.Getenv(crossAccountLinkedProfileEnvVar)
+	if linkedProfile == "" {
+		t.Skipf("skipping cross-account attachment test; set %s to a linked-account AWS CLI profile to run it", crossAccountLinkedProfileEnvVar)
+	}
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	linkedSess, err := session.NewSessionWithOptions(session.Options{
+		Profile:           linkedProfile,
+		SharedConfigState: session.SharedConfigEnable,
+	})
+	require.NoError(t, err, "Failed to create AWS session for the linked-account profile")
+
+	linkedIdentity := awsAccountIDForTest(t, linkedSess)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":         awsRegion,
+			"name_prefix":        uniquePrefix,
+			"linked_account_ids": []string{linkedIdentity},
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	attachmentARN := terraform.Output(t, terraformOptions, "attachment_arn")
+	linkedRoleARN := terraform.Output(t, terraformOptions, "linked_account_role_arn")
+	queueURL := terraform.Output(t, terraformOptions, "queue_url")
+	purchaserLambdaName := terraform.Output(t, terraformOptions, "purchaser_lambda_name")
+	require.NotEmpty(t, attachmentARN, "Attachment ARN should not be empty")
+	require.NotEmpty(t, linkedRoleARN, "Linked account role ARN should not be empty")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+
+	iamClient := iam.New(sess)
+	sqsClient := sqs.New(sess)
+	lambdaClient := lambda.New(sess)
+
+	t.Log("Validating the attachment authorizes the linked account's role...")
+	attachmentDoc := findInlinePolicyDocumentContaining(t, iamClient, roleNameFromARN(linkedRoleARN), "savingsplans:CreateSavingsPlan")
+	assert.Contains(t, attachmentDoc, "savingsplans:CreateSavingsPlan", "Linked account role should grant savingsplans:CreateSavingsPlan")
+
+	t.Log("Enqueuing a dry-run cross-account purchase intent...")
+	_, err = sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl: aws.String(queueURL),
+		MessageBody: aws.String(fmt.Sprintf(`{"recommendation_hash": %q, "savings_plan_type": "COMPUTE_SP", "term": "ONE_YEAR", "payment_option": "NO_UPFRONT", "hourly_commitment": "1.00", "target_account_id": %q}`,
+			uniquePrefix+"-cross-account-hash", linkedIdentity)),
+	})
+	require.NoError(t, err, "Failed to send cross-account purchase intent")
+
+	t.Log("Invoking the Purchaser to process the cross-account purchase intent...")
+	invokeResult, err := lambdaClient.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(purchaserLambdaName),
+		Payload:      []byte("{}"),
+	})
+	require.NoError(t, err, "Failed to invoke Purchaser Lambda")
+	assert.Nil(t, invokeResult.FunctionError, "Purchaser invocation should not report a function error")
+
+	t.Log("Verifying the AssumeRole and downstream API call appear in the linked account's CloudTrail...")
+
+	linkedTrailClient := cloudtrail.New(linkedSess)
+	require.Eventually(t, func() bool {
+		events, err := linkedTrailClient.LookupEvents(&cloudtrail.LookupEventsInput{
+			LookupAttributes: []*cloudtrail.LookupAttribute{
+				{AttributeKey: aws.String("EventName"), AttributeValue: aws.String("AssumeRole")},
+			},
+			StartTime: aws.Time(time.Now().Add(-10 * time.Minute)),
+		})
+		return err == nil && len(events.Events) > 0
+	}, 3*time.Minute, 10*time.Second, "No AssumeRole event observed in the linked account's CloudTrail")
+
+	require.Eventually(t, func() bool {
+		events, err := linkedTrailClient.LookupEvents(&cloudtrail.LookupEventsInput{
+			LookupAttributes: []*cloudtrail.LookupAttribute{
+				{AttributeKey: aws.String("EventName"), AttributeValue: aws.String("CreateSavingsPlan")},
+			},
+			StartTime: aws.Time(time.Now().Add(-10 * time.Minute)),
+		})
+		return err == nil && len(events.Events) > 0
+	}, 3*time.Minute, 10*time.Second, "No CreateSavingsPlan call observed in the linked account's CloudTrail")
+
+	t.Log("✓ Cross-account purchaser attachment validated via linked-account CloudTrail")
+}
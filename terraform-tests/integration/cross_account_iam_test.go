@@ -0,0 +1,146 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestCrossAccountIAMPermissions deploys the module with payer_account_role_arn
+// and linked_account_role_arns set and validates the resulting AssumeRole
+// statements, rather than relying on a real second AWS account: it stands up
+// a second role in this same account as a stand-in payer/linked role, so the
+// ARNs are real and assumable even though they're not in a different
+// account.
+func TestCrossAccountIAMPermissions(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+	iamClient := iam.New(sess)
+
+	payerRoleARN := createStandInAssumableRole(t, iamClient, uniquePrefix+"-payer-standin")
+	defer deleteStandInAssumableRole(t, iamClient, uniquePrefix+"-payer-standin")
+
+	linkedRoleARN := createStandInAssumableRole(t, iamClient, uniquePrefix+"-linked-standin")
+	defer deleteStandInAssumableRole(t, iamClient, uniquePrefix+"-linked-standin")
+
+	const externalID = "sp-autopilot-test-external-id"
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":               awsRegion,
+			"name_prefix":              uniquePrefix,
+			"payer_account_role_arn":   payerRoleARN,
+			"linked_account_role_arns": []string{linkedRoleARN},
+			"external_id":              externalID,
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	schedulerAssumeRoleARNs := terraform.OutputList(t, terraformOptions, "scheduler_assume_role_arns")
+	purchaserAssumeRoleARN := terraform.Output(t, terraformOptions, "purchaser_assume_role_arn")
+	assert.Contains(t, schedulerAssumeRoleARNs, linkedRoleARN, "scheduler_assume_role_arns should include the linked account role")
+	assert.Equal(t, payerRoleARN, purchaserAssumeRoleARN, "purchaser_assume_role_arn should be the payer account role")
+
+	schedulerRoleARN := terraform.Output(t, terraformOptions, "scheduler_role_arn")
+	purchaserRoleARN := terraform.Output(t, terraformOptions, "purchaser_role_arn")
+
+	t.Log("Validating the Scheduler role's sts:AssumeRole statement targets the linked account role...")
+	assertAssumeRoleStatement(t, iamClient, roleNameFromARN(schedulerRoleARN), linkedRoleARN, externalID)
+
+	t.Log("Validating the Purchaser role's sts:AssumeRole statement targets the payer account role...")
+	assertAssumeRoleStatement(t, iamClient, roleNameFromARN(purchaserRoleARN), payerRoleARN, externalID)
+
+	t.Log("Validating the Purchaser role no longer grants local-account savingsplans:CreateSavingsPlan...")
+	output, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleNameFromARN(purchaserRoleARN))})
+	require.NoError(t, err, "Failed to list Purchaser role inline policies")
+	assert.NotContains(t, policyNames(output), "savingsplans", "Purchaser role should not carry a local-account savingsplans policy when payer_account_role_arn is set")
+
+	t.Log("✓ Cross-account IAM permissions validated")
+}
+
+// assertAssumeRoleStatement fails the test unless one of roleName's inline
+// policies grants sts:AssumeRole on targetRoleARN with an sts:ExternalId
+// condition matching externalID.
+func assertAssumeRoleStatement(t *testing.T, iamClient *iam.IAM, roleName, targetRoleARN, externalID string) {
+	listOutput, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	require.NoError(t, err, "Failed to list inline policies for role %s", roleName)
+
+	for _, policyName := range listOutput.PolicyNames {
+		getOutput, err := iamClient.GetRolePolicy(&iam.GetRolePolicyInput{
+			RoleName:   aws.String(roleName),
+			PolicyName: policyName,
+		})
+		require.NoError(t, err, "Failed to get inline policy %s for role %s", *policyName, roleName)
+
+		document := aws.StringValue(getOutput.PolicyDocument)
+		if containsAll(document, "sts:AssumeRole", targetRoleARN, externalID) {
+			return
+		}
+	}
+
+	t.Fatalf("no inline policy on role %s grants sts:AssumeRole on %s with ExternalId %s", roleName, targetRoleARN, externalID)
+}
+
+func containsAll(haystack string, needles ...string) bool {
+	for _, needle := range needles {
+		if !strings.Contains(haystack, needle) {
+			return false
+		}
+	}
+	return true
+}
+
+// policyNames flattens a ListRolePoliciesOutput to plain strings.
+func policyNames(output *iam.ListRolePoliciesOutput) []string {
+	var names []string
+	for _, name := range output.PolicyNames {
+		names = append(names, *name)
+	}
+	return names
+}
+
+// createStandInAssumableRole creates a minimal role this test's own caller
+// can assume, standing in for a role in a separate linked/payer account.
+func createStandInAssumableRole(t *testing.T, iamClient *iam.IAM, roleName string) string {
+	trustPolicy := `{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"AWS": "*"},
+			"Action": "sts:AssumeRole"
+		}]
+	}`
+
+	createOutput, err := iamClient.CreateRole(&iam.CreateRoleInput{
+		RoleName:                 aws.String(roleName),
+		AssumeRolePolicyDocument: aws.String(trustPolicy),
+	})
+	require.NoError(t, err, "Failed to create stand-in role %s", roleName)
+	return *createOutput.Role.Arn
+}
+
+func deleteStandInAssumableRole(t *testing.T, iamClient *iam.IAM, roleName string) {
+	_, err := iamClient.DeleteRole(&iam.DeleteRoleInput{RoleName: aws.String(roleName)})
+	if err != nil {
+		t.Logf("Warning: failed to delete stand-in role %s: %v", roleName, err)
+	}
+}
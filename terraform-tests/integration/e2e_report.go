@@ -0,0 +1,139 @@
+package test
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// e2eReportDirEnvVar names the directory TestFullDeploymentAndCleanup writes
+// its structured phase report to. Unset (the default) means reporting is
+// skipped entirely.
+const e2eReportDirEnvVar = "SP_AUTOPILOT_E2E_REPORT_DIR"
+
+// e2ePhase is one named, timed step of the end-to-end test (Phase 0 plan
+// validation, Phase 1 deployment, Phase 2 resource validation, and so on),
+// recorded so CI dashboards can see which phase failed and how long it took
+// instead of scraping t.Log output.
+type e2ePhase struct {
+	Name      string    `json:"name"`
+	StartedAt time.Time `json:"started_at"`
+	EndedAt   time.Time `json:"ended_at"`
+	Passed    bool      `json:"passed"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// e2eReport accumulates phases across a single TestFullDeploymentAndCleanup
+// run and, when e2eReportDirEnvVar is set, writes both a machine-readable
+// JSON report (keyed by the resourceIdentifiers map already built for Phase
+// 4) and a JUnit XML file with one <testcase> per phase.
+type e2eReport struct {
+	SchedulerEngine     string            `json:"scheduler_engine"`
+	Phases              []e2ePhase        `json:"phases"`
+	ResourceIdentifiers map[string]string `json:"resource_identifiers,omitempty"`
+}
+
+// startPhase opens a new phase and returns a func that closes it, recording
+// start/end time and whether the phase left any new test failure behind
+// (t.Failed() is cumulative, so this also captures whether the test had
+// already failed before the phase started). Called as:
+//
+//	end := report.startPhase(t, "Phase 2: Resource Validation")
+//	... phase body ...
+//	end()
+//
+// A require.* failure inside the phase body aborts the goroutine via
+// t.FailNow() before end() runs, so that phase (and anything after it)
+// simply won't appear in the report - the test's own pass/fail output is
+// still authoritative, this only adds per-phase timing on top.
+func (r *e2eReport) startPhase(t *testing.T, name string) func() {
+	phase := e2ePhase{Name: name, StartedAt: time.Now()}
+	passedBefore := !t.Failed()
+
+	return func() {
+		phase.EndedAt = time.Now()
+		phase.Passed = passedBefore && !t.Failed()
+		if !phase.Passed {
+			phase.Error = fmt.Sprintf("phase %q reported a test failure; see t.Log output above for details", name)
+		}
+		r.Phases = append(r.Phases, phase)
+	}
+}
+
+// write saves the JSON and JUnit reports under dir if e2eReportDirEnvVar is
+// set, and logs where they landed. It never fails the test - a broken
+// report writer shouldn't mask a real infrastructure failure.
+func (r *e2eReport) write(t *testing.T) {
+	dir := os.Getenv(e2eReportDirEnvVar)
+	if dir == "" {
+		return
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Logf("⚠ Failed to create %s: %v", dir, err)
+		return
+	}
+
+	jsonPath := filepath.Join(dir, "e2e-report.json")
+	if data, err := json.MarshalIndent(r, "", "  "); err != nil {
+		t.Logf("⚠ Failed to marshal e2e report: %v", err)
+	} else if err := os.WriteFile(jsonPath, data, 0644); err != nil {
+		t.Logf("⚠ Failed to write %s: %v", jsonPath, err)
+	} else {
+		t.Logf("E2E JSON report written to %s", jsonPath)
+	}
+
+	junitPath := filepath.Join(dir, "e2e-report.junit.xml")
+	if data, err := xml.MarshalIndent(r.toJUnitSuite(), "", "  "); err != nil {
+		t.Logf("⚠ Failed to marshal JUnit report: %v", err)
+	} else if err := os.WriteFile(junitPath, append([]byte(xml.Header), data...), 0644); err != nil {
+		t.Logf("⚠ Failed to write %s: %v", junitPath, err)
+	} else {
+		t.Logf("E2E JUnit report written to %s", junitPath)
+	}
+}
+
+// junitTestSuite/junitTestCase are a minimal subset of the JUnit XML schema
+// - just enough for standard CI test viewers to render one row per phase.
+type junitTestSuite struct {
+	XMLName  xml.Name        `xml:"testsuite"`
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string        `xml:"name,attr"`
+	Time    float64       `xml:"time,attr"`
+	Failure *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+}
+
+func (r *e2eReport) toJUnitSuite() junitTestSuite {
+	suite := junitTestSuite{
+		Name:  fmt.Sprintf("TestFullDeploymentAndCleanup[%s]", r.SchedulerEngine),
+		Tests: len(r.Phases),
+	}
+
+	for _, phase := range r.Phases {
+		testCase := junitTestCase{
+			Name: phase.Name,
+			Time: phase.EndedAt.Sub(phase.StartedAt).Seconds(),
+		}
+		if !phase.Passed {
+			suite.Failures++
+			testCase.Failure = &junitFailure{Message: phase.Error}
+		}
+		suite.Cases = append(suite.Cases, testCase)
+	}
+
+	return suite
+}
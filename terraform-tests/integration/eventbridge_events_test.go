@@ -0,0 +1,158 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+const eventBridgeEventPollTimeout = 3 * time.Minute
+const eventBridgeEventPollInterval = 10 * time.Second
+
+// TestEventBridgeStructuredEvents deploys the module with
+// enable_eventbridge_events=true and proves a purchase-intent message on the
+// main queue results in a structured `sp.autopilot` event on the configured
+// event bus: it registers a throwaway EventBridge rule targeting a temporary
+// SQS queue, sends a synthetic message carrying a known correlation id, and
+// waits for a matching PurchaseIntentProposed event to show up on that queue.
+func TestEventBridgeStructuredEvents(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":                awsRegion,
+			"name_prefix":               uniquePrefix,
+			"enable_eventbridge_events": true,
+			"event_bus_name":            "default",
+			"enable_firehose_archive":   false,
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	queueURL := terraform.Output(t, terraformOptions, "queue_url")
+	schemaVersion := terraform.Output(t, terraformOptions, "eventbridge_event_schema_version")
+	require.NotEmpty(t, queueURL, "Queue URL should not be empty")
+	require.NotEmpty(t, schemaVersion, "EventBridge event schema version output should not be empty")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+
+	eventsClient := cloudwatchevents.New(sess)
+	sqsClient := sqs.New(sess)
+
+	t.Log("Registering a test-only EventBridge rule targeting a capture queue...")
+
+	ruleName := uniquePrefix + "-sp-autopilot-event-capture"
+	_, err = eventsClient.PutRule(&cloudwatchevents.PutRuleInput{
+		Name:         aws.String(ruleName),
+		EventPattern: aws.String(`{"source":["sp.autopilot"]}`),
+		State:        aws.String("ENABLED"),
+	})
+	require.NoError(t, err, "Failed to create capture rule on the default event bus")
+	defer eventsClient.DeleteRule(&cloudwatchevents.DeleteRuleInput{Name: aws.String(ruleName)})
+
+	captureQueueURL, captureQueueARN := createEventBridgeCaptureQueue(t, sqsClient, eventsClient, ruleName)
+	defer deleteAlarmCaptureQueue(t, sqsClient, captureQueueURL)
+
+	_, err = eventsClient.PutTargets(&cloudwatchevents.PutTargetsInput{
+		Rule: aws.String(ruleName),
+		Targets: []*cloudwatchevents.Target{
+			{Id: aws.String("capture-queue"), Arn: aws.String(captureQueueARN)},
+		},
+	})
+	require.NoError(t, err, "Failed to attach capture queue as an EventBridge target")
+	defer eventsClient.RemoveTargets(&cloudwatchevents.RemoveTargetsInput{
+		Rule: aws.String(ruleName),
+		Ids:  []*string{aws.String("capture-queue")},
+	})
+
+	t.Log("Sending a synthetic purchase-intent message to the main queue...")
+
+	correlationID := uniquePrefix + "-correlation"
+	_, err = sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String(fmt.Sprintf(`{"dry_run": true, "correlation_id": %q}`, correlationID)),
+	})
+	require.NoError(t, err, "Failed to send synthetic purchase-intent message")
+
+	t.Log("Waiting for the matching PurchaseIntentProposed event to reach the capture queue...")
+
+	require.Eventually(t, func() bool {
+		received, err := sqsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(captureQueueURL),
+			MaxNumberOfMessages: aws.Int64(10),
+			WaitTimeSeconds:     aws.Int64(5),
+		})
+		if err != nil {
+			return false
+		}
+		for _, msg := range received.Messages {
+			if strings.Contains(*msg.Body, "PurchaseIntentProposed") && strings.Contains(*msg.Body, correlationID) {
+				return true
+			}
+		}
+		return false
+	}, eventBridgeEventPollTimeout, eventBridgeEventPollInterval, "Did not observe a PurchaseIntentProposed event for this test's correlation id")
+
+	t.Log("✓ Structured EventBridge event observed")
+}
+
+// createEventBridgeCaptureQueue creates a temporary SQS queue with an access
+// policy scoped to the given EventBridge rule, mirroring
+// createAlarmCaptureQueue's SNS-topic-scoped policy for the events.amazonaws.com
+// principal instead of sns.amazonaws.com.
+func createEventBridgeCaptureQueue(t *testing.T, sqsClient *sqs.SQS, eventsClient *cloudwatchevents.CloudWatchEvents, ruleName string) (queueURL, queueARN string) {
+	createOutput, err := sqsClient.CreateQueue(&sqs.CreateQueueInput{
+		QueueName: aws.String(ruleName + "-capture"),
+	})
+	require.NoError(t, err, "Failed to create EventBridge capture queue")
+	queueURL = *createOutput.QueueUrl
+
+	attrsOutput, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []*string{aws.String("QueueArn")},
+	})
+	require.NoError(t, err, "Failed to get EventBridge capture queue ARN")
+	queueARN = *attrsOutput.Attributes["QueueArn"]
+
+	ruleOutput, err := eventsClient.DescribeRule(&cloudwatchevents.DescribeRuleInput{Name: aws.String(ruleName)})
+	require.NoError(t, err, "Failed to describe capture rule")
+
+	policy := fmt.Sprintf(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Service": "events.amazonaws.com"},
+			"Action": "sqs:SendMessage",
+			"Resource": "%s",
+			"Condition": {"ArnEquals": {"aws:SourceArn": "%s"}}
+		}]
+	}`, queueARN, *ruleOutput.Arn)
+
+	_, err = sqsClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		Attributes: map[string]*string{
+			"Policy": aws.String(policy),
+		},
+	})
+	require.NoError(t, err, "Failed to set EventBridge capture queue policy")
+
+	return queueURL, queueARN
+}
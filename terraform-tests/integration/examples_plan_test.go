@@ -0,0 +1,132 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tfjson "github.com/hashicorp/terraform-json"
+
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+// runExamplePlanTest runs `terraform init` + `terraform plan -out=...` +
+// `terraform show -json` against exampleDir and hands the resulting plan to
+// each assertion. Unlike the apply-based example tests, this never calls
+// InitAndApply/Destroy, so it's fast, free, and doesn't need real AWS
+// resources to exist afterward - only enough credentials (real or dummy) for
+// the AWS provider to initialize. Pointing TF_CLI_CONFIG_FILE at a dev
+// overrides config with a mocked "aws" provider binary removes that
+// credentials requirement entirely; this helper doesn't care either way, it
+// just plans whatever provider WithDefaultRetryableErrors resolves.
+func runExamplePlanTest(t *testing.T, exampleDir string, vars map[string]interface{}, assertions ...func(t *testing.T, plan *tfjson.Plan)) {
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: exampleDir,
+		Vars:         vars,
+		NoColor:      true,
+		PlanFilePath: filepath.Join(t.TempDir(), "tfplan"),
+	})
+
+	plan := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+
+	for _, assertion := range assertions {
+		assertion(t, &plan.RawPlan)
+	}
+}
+
+// plannedLambdaEnvVar finds the planned aws_lambda_function resource whose
+// address contains resourceAddressSubstr and returns the planned value of one
+// of its environment variables. It asserts on the planned configuration
+// directly, so it works without ever applying the plan.
+func plannedLambdaEnvVar(t *testing.T, plan *tfjson.Plan, resourceAddressSubstr string, envVarName string) string {
+	for _, change := range plan.ResourceChanges {
+		if change.Type != "aws_lambda_function" || !strings.Contains(change.Address, resourceAddressSubstr) {
+			continue
+		}
+
+		after, ok := change.Change.After.(map[string]interface{})
+		require.True(t, ok, "planned aws_lambda_function %s has no 'after' values", change.Address)
+
+		environments, ok := after["environment"].([]interface{})
+		if !ok || len(environments) == 0 {
+			return ""
+		}
+
+		environment, ok := environments[0].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+
+		variables, ok := environment["variables"].(map[string]interface{})
+		if !ok {
+			return ""
+		}
+
+		if val, ok := variables[envVarName]; ok {
+			return val.(string)
+		}
+		return ""
+	}
+
+	t.Fatalf("no planned aws_lambda_function resource found matching %q", resourceAddressSubstr)
+	return ""
+}
+
+// planLambdaEnv adapts a *tfjson.Plan to lambdaEnvLookup, so an exampleCase's
+// assert closure can run against a plan exactly as it does against a live
+// LambdaEnv: role ("scheduler"/"purchaser"/"reporter") matches the resource
+// address substring plannedLambdaEnvVar filters aws_lambda_function changes by.
+type planLambdaEnv struct {
+	t    *testing.T
+	plan *tfjson.Plan
+}
+
+func (e planLambdaEnv) Var(role, envVarName string) string {
+	return plannedLambdaEnvVar(e.t, e.plan, role, envVarName)
+}
+
+// TestExamplesPlan runs every case in exampleCases through runExamplePlanTest
+// and checks the exact same assert closures TestExamples does, against the
+// planned configuration instead of a live apply. This is what PR CI runs:
+// it's fast, free, and doesn't need real AWS resources to exist afterward.
+//
+// modeLocalSource cases are rewritten to the local module checkout exactly as
+// runExampleCase does for TestExamples - otherwise this would plan against
+// the registry-published module source, and PR CI's fast gate would never
+// catch a local module regression in those examples.
+func TestExamplesPlan(t *testing.T) {
+	for _, c := range exampleCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			planDir := c.dir
+			namePrefix := ""
+
+			switch c.mode {
+			case modeLocalSource:
+				// NOT using t.Parallel(): matches runExampleCase, which
+				// avoids it because applying several cases at once trips
+				// IAM rate limits, even though planning alone doesn't hit
+				// them.
+				namePrefix = fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+				testDir := prepareExampleForTesting(t, c.dir, namePrefix)
+				defer os.RemoveAll(testDir)
+				planDir = testDir
+			case modeRegistrySource:
+				t.Parallel()
+			}
+
+			vars := commonExampleVars(namePrefix)
+			if c.extraVars != nil {
+				c.extraVars(vars)
+			}
+
+			runExamplePlanTest(t, planDir, vars, func(t *testing.T, plan *tfjson.Plan) {
+				c.assert(t, planLambdaEnv{t: t, plan: plan})
+			})
+		})
+	}
+}
@@ -1,258 +1,310 @@
 package test
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/etiennechabert/terraform-aws-sp-autopilot/terraform-tests/internal/exampleprep"
 	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/logger"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	terratesting "github.com/gruntwork-io/terratest/modules/testing"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
-// commonExampleValidation performs validation common to all examples
-func commonExampleValidation(t *testing.T, terraformOptions *terraform.Options, awsRegion string) {
-	// Validate core resources exist
-	queueURL := terraform.Output(t, terraformOptions, "queue_url")
-	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
-	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
-
-	require.NotEmpty(t, queueURL, "Queue URL should not be empty")
-	require.NotEmpty(t, snsTopicARN, "SNS topic ARN should not be empty")
-	require.NotEmpty(t, schedulerLambdaName, "Scheduler Lambda name should not be empty")
-
-	t.Logf("✓ Core resources validated: queue=%s, lambda=%s", queueURL, schedulerLambdaName)
+// requireApplyTests skips the calling test unless SP_AUTOPILOT_APPLY_TESTS=1 is
+// set. These tests go all the way through InitAndApply/Destroy against real
+// AWS, which costs money and hits IAM rate limits, so PR CI relies on the
+// plan-only equivalents in examples_plan_test.go instead; only nightly/manual
+// runs opt into the full apply/destroy path.
+func requireApplyTests(t *testing.T) {
+	if os.Getenv("SP_AUTOPILOT_APPLY_TESTS") != "1" {
+		t.Skip("skipping apply/destroy example test; set SP_AUTOPILOT_APPLY_TESTS=1 to run it")
+	}
 }
 
-// getLambdaEnvVar retrieves an environment variable from a Lambda function
-func getLambdaEnvVar(t *testing.T, awsRegion string, functionName string, envVarName string) string {
-	lambdaClient := terratest_aws.NewLambdaClient(t, awsRegion)
+// cleanLogger implements logger.TestLogger interface to strip verbose prefixes
+type cleanLogger struct{}
 
-	config, err := lambdaClient.GetFunction(&lambda.GetFunctionInput{
-		FunctionName: aws.String(functionName),
-	})
-	require.NoError(t, err, "Failed to get Lambda function configuration")
-	require.NotNil(t, config.Configuration, "Lambda configuration should not be nil")
-	require.NotNil(t, config.Configuration.Environment, "Lambda environment should not be nil")
+func (l *cleanLogger) Logf(_ terratesting.TestingT, format string, args ...interface{}) {
+	// Format the message and print directly without test name/timestamp prefix
+	msg := fmt.Sprintf(format, args...)
+	fmt.Println(msg)
+}
 
-	if val, ok := config.Configuration.Environment.Variables[envVarName]; ok {
-		return *val
-	}
+func getCleanLogger() *logger.Logger {
+	return logger.New(&cleanLogger{})
+}
 
-	return ""
+// lambdaEnvLookup resolves a role ("scheduler", "purchaser", "reporter") to
+// one of that Lambda's configured environment variables. LambdaEnv (below)
+// implements it against a live apply via GetFunction; planLambdaEnv in
+// examples_plan_test.go implements it against a `terraform plan` instead -
+// an exampleCase's assert closure is written against this interface so the
+// exact same assertions run in both TestExamples and TestExamplesPlan.
+type lambdaEnvLookup interface {
+	Var(role, envVarName string) string
 }
 
-// TestExampleSingleAccountCompute validates the single-account-compute example
-// Focus: Compute SP with mixed term/payment options (3-year + 1-year, all-upfront + partial-upfront)
-func TestExampleSingleAccountCompute(t *testing.T) {
-	t.Parallel()
+// LambdaEnv resolves a role to its Lambda function's live environment
+// variables, caching each role's GetFunction result so an exampleCase that
+// asserts on several env vars for the same function only fetches it once.
+type LambdaEnv struct {
+	t             *testing.T
+	awsRegion     string
+	terraformOpts *terraform.Options
+	cache         map[string]*lambda.GetFunctionOutput
+}
 
-	awsRegion := "us-east-1"
+func newLambdaEnv(t *testing.T, awsRegion string, terraformOpts *terraform.Options) *LambdaEnv {
+	return &LambdaEnv{t: t, awsRegion: awsRegion, terraformOpts: terraformOpts, cache: map[string]*lambda.GetFunctionOutput{}}
+}
 
-	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
-		TerraformDir: "../../examples/single-account-compute",
-		Vars: map[string]interface{}{
-			// Override schedules to far future for safety
-			"scheduler": map[string]interface{}{
-				"scheduler": "cron(0 0 1 1 ? 2099)",
-				"purchaser": "cron(0 0 1 1 ? 2099)",
-				"reporter":  "cron(0 0 1 1 ? 2099)",
-			},
-			// Override to dry-run for safety
-			"lambda_config": map[string]interface{}{
-				"scheduler": map[string]interface{}{
-					"dry_run": true,
-				},
-			},
-		},
-		NoColor: true,
-	})
+// Var returns envVarName from the Lambda function behind role's Terraform
+// output (e.g. role "scheduler" resolves via the scheduler_lambda_name output).
+func (e *LambdaEnv) Var(role, envVarName string) string {
+	cfg, ok := e.cache[role]
+	if !ok {
+		functionName := terraform.Output(e.t, e.terraformOpts, role+"_lambda_name")
+		lambdaClient := terratest_aws.NewLambdaClient(e.t, e.awsRegion)
+
+		var err error
+		cfg, err = lambdaClient.GetFunction(&lambda.GetFunctionInput{FunctionName: aws.String(functionName)})
+		require.NoError(e.t, err, "Failed to get Lambda function configuration for role %q", role)
+		e.cache[role] = cfg
+	}
 
-	defer terraform.Destroy(t, terraformOptions)
+	require.NotNil(e.t, cfg.Configuration, "Lambda configuration should not be nil")
+	require.NotNil(e.t, cfg.Configuration.Environment, "Lambda environment should not be nil")
 
-	t.Log("Testing single-account-compute example...")
-	terraform.InitAndApply(t, terraformOptions)
+	if val, ok := cfg.Configuration.Environment.Variables[envVarName]; ok {
+		return *val
+	}
+	return ""
+}
 
-	// Common validation
-	commonExampleValidation(t, terraformOptions, awsRegion)
+// exampleSourceMode controls how an exampleCase's Terraform directory is
+// prepared before it's applied or planned.
+type exampleSourceMode int
 
-	// Unique validation: Verify compute SP enabled with term mix
-	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
+const (
+	// modeRegistrySource applies the example exactly as published, against
+	// its own on-disk directory and default name_prefix.
+	modeRegistrySource exampleSourceMode = iota
 
-	enableComputeSP := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ENABLE_COMPUTE_SP")
-	assert.Equal(t, "true", enableComputeSP, "Compute SP should be enabled")
+	// modeLocalSource rewrites the module source to the local checkout (via
+	// exampleprep) and assigns a unique name_prefix per run, so the case is
+	// safe to re-run without colliding with a previous run's resources.
+	modeLocalSource
+)
 
-	enableDatabaseSP := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ENABLE_DATABASE_SP")
-	assert.Equal(t, "false", enableDatabaseSP, "Database SP should be disabled")
+// exampleCase is one example directory exercised by both TestExamples
+// (apply, in examples_test.go) and TestExamplesPlan (plan-only, in
+// examples_plan_test.go). assert is written against lambdaEnvLookup so the
+// same assertions run against a live apply or a plan.
+type exampleCase struct {
+	name string
+	dir  string
+	mode exampleSourceMode
 
-	// Verify term mix is configured (example uses 50% 3-year, 30% 1-year, 20% 1-year partial)
-	computeTermMix := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "COMPUTE_SP_TERM_MIX")
-	assert.NotEmpty(t, computeTermMix, "Compute term mix should be configured")
-	assert.Contains(t, computeTermMix, "three_year", "Term mix should include 3-year plans")
-	assert.Contains(t, computeTermMix, "one_year", "Term mix should include 1-year plans")
+	// extraVars mutates the common scheduler cron-override + dry-run vars
+	// every case gets (e.g. to add assume_role_arn overrides). Optional.
+	extraVars func(vars map[string]interface{})
 
-	t.Log("✓ single-account-compute example validated: mixed term/payment options confirmed")
+	assert func(t *testing.T, env lambdaEnvLookup)
 }
 
-// TestExampleDatabaseOnly validates the database-only example
-// Focus: Database SP only (no compute), validates database-specific configuration
-func TestExampleDatabaseOnly(t *testing.T) {
-	t.Parallel()
-
-	awsRegion := "us-east-1"
+// exampleCases enumerates every example the harness exercises. See
+// exampleSourceMode for what distinguishes the two modes below.
+var exampleCases = []exampleCase{
+	{
+		// Focus: Compute SP with mixed term/payment options (3-year + 1-year).
+		name: "SingleAccountCompute",
+		dir:  "../../examples/single-account-compute",
+		mode: modeLocalSource,
+		assert: func(t *testing.T, env lambdaEnvLookup) {
+			assert.Equal(t, "true", env.Var("scheduler", "ENABLE_COMPUTE_SP"), "Compute SP should be enabled")
+			assert.Equal(t, "false", env.Var("scheduler", "ENABLE_DATABASE_SP"), "Database SP should be disabled")
+
+			computeTermMix := env.Var("scheduler", "COMPUTE_SP_TERM_MIX")
+			assert.NotEmpty(t, computeTermMix, "Compute term mix should be configured")
+			assert.Contains(t, computeTermMix, "three_year", "Term mix should include 3-year plans")
+			assert.Contains(t, computeTermMix, "one_year", "Term mix should include 1-year plans")
+		},
+	},
+	{
+		// Focus: Database SP only (no compute), database-specific configuration.
+		name: "DatabaseOnly",
+		dir:  "../../examples/database-only",
+		mode: modeRegistrySource,
+		assert: func(t *testing.T, env lambdaEnvLookup) {
+			assert.Equal(t, "false", env.Var("scheduler", "ENABLE_COMPUTE_SP"), "Compute SP should be disabled")
+			assert.Equal(t, "true", env.Var("scheduler", "ENABLE_DATABASE_SP"), "Database SP should be enabled")
+			assert.Equal(t, "false", env.Var("scheduler", "ENABLE_SAGEMAKER_SP"), "SageMaker SP should be disabled")
+
+			// Database SP always uses NO_UPFRONT, ONE_YEAR (AWS constraint)
+			assert.Equal(t, "NO_UPFRONT", env.Var("scheduler", "DATABASE_SP_PAYMENT_OPTION"), "Database SP should use NO_UPFRONT")
+			assert.Equal(t, "ONE_YEAR", env.Var("scheduler", "DATABASE_SP_TERM"), "Database SP should use ONE_YEAR term")
+		},
+	},
+	{
+		// Focus: Dichotomy purchase strategy with adaptive purchase sizing.
+		name: "DichotomyStrategy",
+		dir:  "../../examples/dichotomy-strategy",
+		mode: modeLocalSource,
+		assert: func(t *testing.T, env lambdaEnvLookup) {
+			assert.Equal(t, "dichotomy", env.Var("scheduler", "PURCHASE_STRATEGY_TYPE"), "Strategy should be dichotomy")
+			assert.Equal(t, "50", env.Var("scheduler", "MAX_PURCHASE_PERCENT"), "Max purchase should be 50% (example config)")
+			assert.Equal(t, "1", env.Var("scheduler", "MIN_PURCHASE_PERCENT"), "Min purchase should be 1% (example config)")
+		},
+	},
+	{
+		// Focus: Cross-account assume_role_arn configuration for AWS Organizations.
+		name: "Organizations",
+		dir:  "../../examples/organizations",
+		mode: modeRegistrySource,
+		extraVars: func(vars map[string]interface{}) {
+			lambdaConfig := vars["lambda_config"].(map[string]interface{})
+			lambdaConfig["scheduler"].(map[string]interface{})["assume_role_arn"] = "arn:aws:iam::999999999999:role/TestSchedulerRole"
+			lambdaConfig["purchaser"] = map[string]interface{}{"assume_role_arn": "arn:aws:iam::999999999999:role/TestPurchaserRole"}
+			lambdaConfig["reporter"] = map[string]interface{}{"assume_role_arn": "arn:aws:iam::999999999999:role/TestReporterRole"}
+		},
+		assert: func(t *testing.T, env lambdaEnvLookup) {
+			assert.Contains(t, env.Var("scheduler", "ASSUME_ROLE_ARN"), "TestSchedulerRole", "Scheduler should have assume_role_arn configured")
+			assert.Contains(t, env.Var("purchaser", "ASSUME_ROLE_ARN"), "TestPurchaserRole", "Purchaser should have assume_role_arn configured")
+			assert.Contains(t, env.Var("reporter", "ASSUME_ROLE_ARN"), "TestReporterRole", "Reporter should have assume_role_arn configured")
+
+			// Both compute and database SP are enabled, for org-wide coverage.
+			assert.Equal(t, "true", env.Var("scheduler", "ENABLE_COMPUTE_SP"), "Compute SP should be enabled for org-wide coverage")
+			assert.Equal(t, "true", env.Var("scheduler", "ENABLE_DATABASE_SP"), "Database SP should be enabled for org-wide coverage")
+		},
+	},
+}
 
-	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
-		TerraformDir: "../../examples/database-only",
-		Vars: map[string]interface{}{
+// commonExampleVars returns the scheduler cron-override + dry-run vars every
+// example case gets, plus name_prefix when namePrefix is set (modeLocalSource).
+func commonExampleVars(namePrefix string) map[string]interface{} {
+	vars := map[string]interface{}{
+		"scheduler": map[string]interface{}{
+			"scheduler": "cron(0 0 1 1 ? 2099)",
+			"purchaser": "cron(0 0 1 1 ? 2099)",
+			"reporter":  "cron(0 0 1 1 ? 2099)",
+		},
+		"lambda_config": map[string]interface{}{
 			"scheduler": map[string]interface{}{
-				"scheduler": "cron(0 0 1 1 ? 2099)",
-				"purchaser": "cron(0 0 1 1 ? 2099)",
-				"reporter":  "cron(0 0 1 1 ? 2099)",
-			},
-			"lambda_config": map[string]interface{}{
-				"scheduler": map[string]interface{}{
-					"dry_run": true,
-				},
+				"dry_run": true,
 			},
 		},
-		NoColor: true,
-	})
-
-	defer terraform.Destroy(t, terraformOptions)
-
-	t.Log("Testing database-only example...")
-	terraform.InitAndApply(t, terraformOptions)
-
-	commonExampleValidation(t, terraformOptions, awsRegion)
-
-	// Unique validation: Verify ONLY database SP is enabled
-	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
-
-	enableComputeSP := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ENABLE_COMPUTE_SP")
-	assert.Equal(t, "false", enableComputeSP, "Compute SP should be disabled")
-
-	enableDatabaseSP := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ENABLE_DATABASE_SP")
-	assert.Equal(t, "true", enableDatabaseSP, "Database SP should be enabled")
-
-	enableSageMakerSP := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ENABLE_SAGEMAKER_SP")
-	assert.Equal(t, "false", enableSageMakerSP, "SageMaker SP should be disabled")
-
-	// Database SP always uses NO_UPFRONT, ONE_YEAR (AWS constraint)
-	databasePaymentOption := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "DATABASE_SP_PAYMENT_OPTION")
-	assert.Equal(t, "NO_UPFRONT", databasePaymentOption, "Database SP should use NO_UPFRONT")
-
-	databaseTerm := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "DATABASE_SP_TERM")
-	assert.Equal(t, "ONE_YEAR", databaseTerm, "Database SP should use ONE_YEAR term")
-
-	t.Log("✓ database-only example validated: database SP configuration confirmed")
+	}
+	if namePrefix != "" {
+		vars["name_prefix"] = namePrefix
+	}
+	return vars
 }
 
-// TestExampleDichotomyStrategy validates the dichotomy-strategy example
-// Focus: Dichotomy purchase strategy with adaptive sizing
-func TestExampleDichotomyStrategy(t *testing.T) {
-	t.Parallel()
+// TestExamples applies every case in exampleCases against real AWS and
+// checks its assertions against the live deployment. It's gated behind
+// requireApplyTests; see examples_plan_test.go for the plan-only equivalent
+// that PR CI runs instead.
+func TestExamples(t *testing.T) {
+	for _, c := range exampleCases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			requireApplyTests(t)
+			runExampleCase(t, c)
+		})
+	}
+}
 
+// runExampleCase prepares c's Terraform directory according to its mode,
+// applies it, validates the core resources every example creates, and hands
+// a LambdaEnv to c.assert.
+func runExampleCase(t *testing.T, c exampleCase) {
 	awsRegion := "us-east-1"
+	terraformDir := c.dir
+	namePrefix := ""
+
+	switch c.mode {
+	case modeLocalSource:
+		// NOT using t.Parallel(): each case already gets its own rewritten
+		// copy and unique name_prefix, but running the IAM role creates of
+		// several cases at once trips AWS's IAM rate limits.
+		namePrefix = fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+		testDir := prepareExampleForTesting(t, c.dir, namePrefix)
+		defer os.RemoveAll(testDir)
+		terraformDir = testDir
+	case modeRegistrySource:
+		// Safe to parallelize: each registry-source case targets its own
+		// example directory, so there's no shared Terraform state to race.
+		t.Parallel()
+	}
+
+	vars := commonExampleVars(namePrefix)
+	if c.extraVars != nil {
+		c.extraVars(vars)
+	}
 
 	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
-		TerraformDir: "../../examples/dichotomy-strategy",
-		Vars: map[string]interface{}{
-			"scheduler": map[string]interface{}{
-				"scheduler": "cron(0 0 1 1 ? 2099)",
-				"purchaser": "cron(0 0 1 1 ? 2099)",
-				"reporter":  "cron(0 0 1 1 ? 2099)",
-			},
-			"lambda_config": map[string]interface{}{
-				"scheduler": map[string]interface{}{
-					"dry_run": true,
-				},
-			},
-		},
-		NoColor: true,
+		TerraformDir: terraformDir,
+		Vars:         vars,
+		NoColor:      true,
+		Logger:       getCleanLogger(),
 	})
 
-	defer terraform.Destroy(t, terraformOptions)
-
-	t.Log("Testing dichotomy-strategy example...")
+	defer func() {
+		// Best-effort cleanup: log errors but don't fail the test. AWS
+		// eventual consistency can cause destroy to fail intermittently;
+		// TestCleanupAllOrphanedResources mops up anything left behind.
+		if err := terraform.DestroyE(t, terraformOptions); err != nil {
+			t.Logf("⚠ Warning: Destroy failed (non-fatal): %v", err)
+			t.Logf("  Resources may need manual cleanup. Run cleanup test if needed.")
+		}
+	}()
 	terraform.InitAndApply(t, terraformOptions)
 
-	commonExampleValidation(t, terraformOptions, awsRegion)
-
-	// Unique validation: Verify dichotomy strategy is configured
+	queueURL := terraform.Output(t, terraformOptions, "queue_url")
+	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
 	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
+	require.NotEmpty(t, queueURL, "Queue URL should not be empty")
+	require.NotEmpty(t, snsTopicARN, "SNS topic ARN should not be empty")
+	require.NotEmpty(t, schedulerLambdaName, "Scheduler Lambda name should not be empty")
+	if namePrefix != "" {
+		assert.Contains(t, schedulerLambdaName, namePrefix+"-scheduler")
+	}
 
-	purchaseStrategyType := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "PURCHASE_STRATEGY_TYPE")
-	assert.Equal(t, "dichotomy", purchaseStrategyType, "Strategy should be dichotomy")
-
-	// Verify dichotomy-specific parameters
-	maxPurchasePercent := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "MAX_PURCHASE_PERCENT")
-	assert.Equal(t, "50", maxPurchasePercent, "Max purchase should be 50% (example config)")
-
-	minPurchasePercent := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "MIN_PURCHASE_PERCENT")
-	assert.Equal(t, "1", minPurchasePercent, "Min purchase should be 1% (example config)")
+	c.assert(t, newLambdaEnv(t, awsRegion, terraformOptions))
 
-	t.Log("✓ dichotomy-strategy example validated: strategy type and parameters confirmed")
+	t.Logf("✓ %s example validated: %s", c.name, c.dir)
 }
 
-// TestExampleOrganizations validates the organizations example
-// Focus: Cross-account assume_role_arn configuration for AWS Organizations
-func TestExampleOrganizations(t *testing.T) {
-	t.Parallel()
-
-	awsRegion := "us-east-1"
-
-	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
-		TerraformDir: "../../examples/organizations",
-		Vars: map[string]interface{}{
-			"scheduler": map[string]interface{}{
-				"scheduler": "cron(0 0 1 1 ? 2099)",
-				"purchaser": "cron(0 0 1 1 ? 2099)",
-				"reporter":  "cron(0 0 1 1 ? 2099)",
-			},
-			"lambda_config": map[string]interface{}{
-				"scheduler": map[string]interface{}{
-					"dry_run":         true,
-					"assume_role_arn": "arn:aws:iam::999999999999:role/TestSchedulerRole", // Fake role for testing
-				},
-				"purchaser": map[string]interface{}{
-					"assume_role_arn": "arn:aws:iam::999999999999:role/TestPurchaserRole", // Fake role for testing
-				},
-				"reporter": map[string]interface{}{
-					"assume_role_arn": "arn:aws:iam::999999999999:role/TestReporterRole", // Fake role for testing
-				},
-			},
+// prepareExampleForTesting creates a copy of exampleDir's .tf files rewritten
+// to source the local module checkout instead of the registry (see
+// terraform-tests/internal/exampleprep), tagged with namePrefix so CI's default_tags
+// requirement is met.
+func prepareExampleForTesting(t *testing.T, exampleDir string, namePrefix string) string {
+	// Use the fixtures approach - copy to integration test directory instead of temp
+	// This allows us to use relative paths like the fixture tests do
+	testDir := filepath.Join("./test-examples", namePrefix)
+
+	// From test-examples/<name>/ to module root is ../../../../
+	err := exampleprep.RewriteExampleForLocalSource(exampleDir, testDir, exampleprep.RewriteOptions{
+		LocalSourcePath: "../../../../",
+		// CI IAM policy requires the ManagedBy = "terratest" tag on every
+		// resource the test creates.
+		DefaultTags: map[string]string{
+			"Environment": "test",
+			"ManagedBy":   "terratest",
 		},
-		NoColor: true,
 	})
+	require.NoError(t, err, "Failed to prepare example for testing")
 
-	defer terraform.Destroy(t, terraformOptions)
-
-	t.Log("Testing organizations example...")
-	terraform.InitAndApply(t, terraformOptions)
-
-	commonExampleValidation(t, terraformOptions, awsRegion)
-
-	// Unique validation: Verify assume_role_arn is configured for each Lambda
-	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
-	purchaserLambdaName := terraform.Output(t, terraformOptions, "purchaser_lambda_name")
-	reporterLambdaName := terraform.Output(t, terraformOptions, "reporter_lambda_name")
-
-	schedulerAssumeRoleArn := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ASSUME_ROLE_ARN")
-	assert.Contains(t, schedulerAssumeRoleArn, "TestSchedulerRole", "Scheduler should have assume_role_arn configured")
-
-	purchaserAssumeRoleArn := getLambdaEnvVar(t, awsRegion, purchaserLambdaName, "ASSUME_ROLE_ARN")
-	assert.Contains(t, purchaserAssumeRoleArn, "TestPurchaserRole", "Purchaser should have assume_role_arn configured")
-
-	reporterAssumeRoleArn := getLambdaEnvVar(t, awsRegion, reporterLambdaName, "ASSUME_ROLE_ARN")
-	assert.Contains(t, reporterAssumeRoleArn, "TestReporterRole", "Reporter should have assume_role_arn configured")
-
-	// Verify both compute and database SP are enabled (organization-wide coverage)
-	enableComputeSP := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ENABLE_COMPUTE_SP")
-	assert.Equal(t, "true", enableComputeSP, "Compute SP should be enabled for org-wide coverage")
-
-	enableDatabaseSP := getLambdaEnvVar(t, awsRegion, schedulerLambdaName, "ENABLE_DATABASE_SP")
-	assert.Equal(t, "true", enableDatabaseSP, "Database SP should be enabled for org-wide coverage")
+	t.Logf("Prepared example in %s", testDir)
 
-	t.Log("✓ organizations example validated: cross-account roles and comprehensive coverage confirmed")
+	return testDir
 }
@@ -0,0 +1,315 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/accessanalyzer"
+	"github.com/aws/aws-sdk-go/service/iam"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// expectedRoleInlinePolicies is the exact set of inline policies every
+// Scheduler/Purchaser role should carry. enforce_exclusive_role_policies
+// makes the module reassert this set on every apply, so anything attached
+// out-of-band - accidentally or by an attacker - is removed rather than
+// merely left uninspected.
+var expectedRoleInlinePolicies = []string{"cloudwatch-logs", "cost-explorer", "sqs", "sns", "savingsplans"}
+
+// TestLambdaIAMPermissions deploys the module and validates the Scheduler and
+// Purchaser roles carry exactly their expected inline policies, including
+// after a rogue policy is attached out-of-band and the module is re-applied.
+func TestLambdaIAMPermissions(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":                      awsRegion,
+			"name_prefix":                     uniquePrefix,
+			"enforce_exclusive_role_policies": true,
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	schedulerRoleARN := terraform.Output(t, terraformOptions, "scheduler_role_arn")
+	purchaserRoleARN := terraform.Output(t, terraformOptions, "purchaser_role_arn")
+	require.NotEmpty(t, schedulerRoleARN, "Scheduler role ARN should not be empty")
+	require.NotEmpty(t, purchaserRoleARN, "Purchaser role ARN should not be empty")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+	iamClient := iam.New(sess)
+
+	schedulerRoleName := roleNameFromARN(schedulerRoleARN)
+	purchaserRoleName := roleNameFromARN(purchaserRoleARN)
+
+	t.Log("Validating the expected inline policy set on both roles...")
+	assertExactInlinePolicies(t, iamClient, schedulerRoleName)
+	assertExactInlinePolicies(t, iamClient, purchaserRoleName)
+	t.Log("✓ Expected inline policies present on both roles")
+
+	t.Run("exclusive_role_policies", func(t *testing.T) {
+		t.Log("Attaching a rogue inline policy to the Purchaser role out-of-band...")
+
+		_, err := iamClient.PutRolePolicy(&iam.PutRolePolicyInput{
+			RoleName:   aws.String(purchaserRoleName),
+			PolicyName: aws.String("rogue-policy"),
+			PolicyDocument: aws.String(`{
+				"Version": "2012-10-17",
+				"Statement": [{"Effect": "Allow", "Action": "s3:*", "Resource": "*"}]
+			}`),
+		})
+		require.NoError(t, err, "Failed to attach rogue inline policy")
+
+		t.Log("Re-applying to confirm the rogue policy is removed...")
+		applyOrSkipIfUnsupported(t, terraformOptions)
+
+		assertExactInlinePolicies(t, iamClient, purchaserRoleName)
+		t.Log("✓ Rogue inline policy was removed by the next apply")
+	})
+
+	t.Run("access_analyzer", func(t *testing.T) {
+		analyzerClient := accessanalyzer.New(sess)
+		deniedActions := []string{"*", "iam:*"}
+
+		for _, roleName := range []string{schedulerRoleName, purchaserRoleName} {
+			for _, policyDocJSON := range loadAllRolePolicies(t, iamClient, roleName) {
+				assertPolicyPassesAccessAnalyzer(t, analyzerClient, policyDocJSON, deniedActions)
+			}
+		}
+
+		t.Log("✓ No Access Analyzer findings or overly-broad actions on either role")
+	})
+}
+
+// loadAllRolePolicies returns the decoded JSON policy document for every
+// policy attached to roleName, inline and managed alike: when the module
+// later moves a permission set to a customer-managed policy, this keeps
+// working without the test suite needing to know which kind it is.
+func loadAllRolePolicies(t *testing.T, iamClient *iam.IAM, roleName string) []string {
+	var documents []string
+
+	inlineOutput, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	require.NoError(t, err, "Failed to list inline policies for role %s", roleName)
+
+	for _, policyName := range inlineOutput.PolicyNames {
+		getOutput, err := iamClient.GetRolePolicy(&iam.GetRolePolicyInput{
+			RoleName:   aws.String(roleName),
+			PolicyName: policyName,
+		})
+		require.NoError(t, err, "Failed to get inline policy %s for role %s", *policyName, roleName)
+		documents = append(documents, parsePolicyDocument(t, aws.StringValue(getOutput.PolicyDocument)))
+	}
+
+	attachedOutput, err := iamClient.ListAttachedRolePolicies(&iam.ListAttachedRolePoliciesInput{RoleName: aws.String(roleName)})
+	require.NoError(t, err, "Failed to list attached managed policies for role %s", roleName)
+
+	for _, attached := range attachedOutput.AttachedPolicies {
+		getPolicy, err := iamClient.GetPolicy(&iam.GetPolicyInput{PolicyArn: attached.PolicyArn})
+		require.NoError(t, err, "Failed to get managed policy %s", *attached.PolicyArn)
+
+		getVersion, err := iamClient.GetPolicyVersion(&iam.GetPolicyVersionInput{
+			PolicyArn: attached.PolicyArn,
+			VersionId: getPolicy.Policy.DefaultVersionId,
+		})
+		require.NoError(t, err, "Failed to get default version of managed policy %s", *attached.PolicyArn)
+		documents = append(documents, parsePolicyDocument(t, aws.StringValue(getVersion.PolicyVersion.Document)))
+	}
+
+	return documents
+}
+
+// parsePolicyDocument decodes the percent-encoded JSON policy document IAM
+// returns from GetRolePolicy/GetPolicyVersion back to plain JSON. Uses
+// url.QueryUnescape rather than a fixed set of strings.ReplaceAll
+// substitutions, which silently corrupted any document containing an
+// escaped character outside that set (spaces, "/", "+", hyphenated
+// condition keys).
+func parsePolicyDocument(t *testing.T, rawPolicyDoc string) string {
+	decoded, err := url.QueryUnescape(rawPolicyDoc)
+	require.NoError(t, err, "Failed to URL-decode policy document")
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(decoded), &parsed), "Failed to parse policy document")
+
+	reencoded, err := json.Marshal(parsed)
+	require.NoError(t, err, "Failed to re-encode parsed policy document")
+	return string(reencoded)
+}
+
+// hasPolicyAction reports whether policyDocJSON's Statement list grants
+// action (allowing for an Action field that's either a single string or a
+// list of strings).
+func hasPolicyAction(policyDocJSON, action string) bool {
+	var doc struct {
+		Statement []struct {
+			Action json.RawMessage `json:"Action"`
+		} `json:"Statement"`
+	}
+	if err := json.Unmarshal([]byte(policyDocJSON), &doc); err != nil {
+		return false
+	}
+
+	for _, statement := range doc.Statement {
+		var single string
+		if err := json.Unmarshal(statement.Action, &single); err == nil {
+			if single == action {
+				return true
+			}
+			continue
+		}
+		var list []string
+		if err := json.Unmarshal(statement.Action, &list); err == nil {
+			for _, a := range list {
+				if a == action {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// TestIAMPolicySimulator deploys the module and runs the Scheduler and
+// Purchaser roles' policies through iam.SimulatePrincipalPolicy against the
+// concrete resource ARNs Terraform actually produced. This catches the class
+// of bug hasPolicyAction can't: an action granted on the wrong resource, not
+// just an action that's missing entirely.
+func TestIAMPolicySimulator(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":  awsRegion,
+			"name_prefix": uniquePrefix,
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	terraform.InitAndApply(t, terraformOptions)
+
+	schedulerRoleARN := terraform.Output(t, terraformOptions, "scheduler_role_arn")
+	purchaserRoleARN := terraform.Output(t, terraformOptions, "purchaser_role_arn")
+	queueARN := terraform.Output(t, terraformOptions, "queue_arn")
+	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+	iamClient := iam.New(sess)
+
+	t.Log("Simulating allowed actions...")
+	for _, sim := range []struct {
+		roleARN     string
+		action      string
+		resourceARN string
+	}{
+		{roleARN: schedulerRoleARN, action: "sqs:SendMessage", resourceARN: queueARN},
+		{roleARN: schedulerRoleARN, action: "sns:Publish", resourceARN: snsTopicARN},
+		{roleARN: purchaserRoleARN, action: "savingsplans:CreateSavingsPlan", resourceARN: "*"},
+	} {
+		assertSimulatedDecision(t, iamClient, sim.roleARN, sim.action, sim.resourceARN, iam.PolicyEvaluationDecisionTypeAllowed)
+	}
+	t.Log("✓ Required actions are allowed on their expected resources")
+
+	t.Log("Simulating denied actions...")
+	for _, sim := range []struct {
+		roleARN     string
+		action      string
+		resourceARN string
+	}{
+		{roleARN: purchaserRoleARN, action: "sqs:PurgeQueue", resourceARN: queueARN},
+		{roleARN: schedulerRoleARN, action: "iam:PassRole", resourceARN: "*"},
+		{roleARN: purchaserRoleARN, action: "iam:PassRole", resourceARN: "*"},
+	} {
+		assertSimulatedDecision(t, iamClient, sim.roleARN, sim.action, sim.resourceARN, iam.PolicyEvaluationDecisionTypeImplicitDeny)
+	}
+	t.Log("✓ Denylisted actions are implicitly denied")
+}
+
+// assertSimulatedDecision runs iam.SimulatePrincipalPolicy for a single
+// (action, resource) pair against roleARN and asserts its EvalDecision
+// matches wantDecision.
+func assertSimulatedDecision(t *testing.T, iamClient *iam.IAM, roleARN, action, resourceARN, wantDecision string) {
+	output, err := iamClient.SimulatePrincipalPolicy(&iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(roleARN),
+		ActionNames:     aws.StringSlice([]string{action}),
+		ResourceArns:    aws.StringSlice([]string{resourceARN}),
+	})
+	require.NoError(t, err, "Failed to simulate %s on %s for role %s", action, resourceARN, roleARN)
+	require.Len(t, output.EvaluationResults, 1, "Expected exactly one evaluation result for %s", action)
+
+	got := aws.StringValue(output.EvaluationResults[0].EvalDecision)
+	assert.Equal(t, wantDecision, got, "Unexpected decision for %s on %s (role %s)", action, resourceARN, roleARN)
+}
+
+// assertPolicyPassesAccessAnalyzer runs policyDocJSON through IAM Access
+// Analyzer's ValidatePolicy (failing on any ERROR/SECURITY_WARNING finding)
+// and CheckAccessNotGranted (failing if any action in deniedActions is
+// actually granted), giving the module a least-privilege regression gate
+// beyond hasPolicyAction's plain string matching.
+func assertPolicyPassesAccessAnalyzer(t *testing.T, analyzerClient *accessanalyzer.AccessAnalyzer, policyDocJSON string, deniedActions []string) {
+	validateOutput, err := analyzerClient.ValidatePolicy(&accessanalyzer.ValidatePolicyInput{
+		PolicyDocument: aws.String(policyDocJSON),
+		PolicyType:     aws.String(accessanalyzer.PolicyTypeIdentityPolicy),
+	})
+	require.NoError(t, err, "Failed to validate policy with Access Analyzer")
+
+	for _, finding := range validateOutput.Findings {
+		severity := aws.StringValue(finding.FindingType)
+		assert.NotEqual(t, accessanalyzer.FindingTypeError, severity, "Access Analyzer reported an ERROR finding: %s", aws.StringValue(finding.IssueCode))
+		assert.NotEqual(t, accessanalyzer.FindingTypeSecurityWarning, severity, "Access Analyzer reported a SECURITY_WARNING finding: %s", aws.StringValue(finding.IssueCode))
+	}
+
+	checkOutput, err := analyzerClient.CheckAccessNotGranted(&accessanalyzer.CheckAccessNotGrantedInput{
+		PolicyDocument: aws.String(policyDocJSON),
+		Access: []*accessanalyzer.Access{
+			{Actions: aws.StringSlice(deniedActions)},
+		},
+	})
+	require.NoError(t, err, "Failed to run CheckAccessNotGranted")
+	assert.Equal(t, accessanalyzer.CheckAccessNotGrantedResultPass, aws.StringValue(checkOutput.Result), "Policy should not grant any of %v", deniedActions)
+}
+
+// assertExactInlinePolicies fails the test unless roleName's inline policies
+// are exactly expectedRoleInlinePolicies, in any order.
+func assertExactInlinePolicies(t *testing.T, iamClient *iam.IAM, roleName string) {
+	output, err := iamClient.ListRolePolicies(&iam.ListRolePoliciesInput{RoleName: aws.String(roleName)})
+	require.NoError(t, err, "Failed to list inline policies for role %s", roleName)
+
+	var names []string
+	for _, name := range output.PolicyNames {
+		names = append(names, *name)
+	}
+	assert.ElementsMatch(t, expectedRoleInlinePolicies, names, "Role %s should carry exactly the expected inline policies", roleName)
+}
+
+// roleNameFromARN extracts the role name from an IAM role ARN
+// (arn:aws:iam::<account>:role/<name>).
+func roleNameFromARN(roleARN string) string {
+	parts := strings.SplitN(roleARN, "role/", 2)
+	return parts[len(parts)-1]
+}
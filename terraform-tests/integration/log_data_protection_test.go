@@ -0,0 +1,198 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/sts"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// schedulerResponseSchemaPath is the on-disk JSON schema the Scheduler
+// Lambda's response must satisfy, versioned alongside RESPONSE_SCHEMA_VERSION.
+const schedulerResponseSchemaPath = "file://./schemas/scheduler_response.v1.json"
+
+const logDataProtectionPollTimeout = 3 * time.Minute
+const logDataProtectionPollInterval = 10 * time.Second
+
+// fakeAccountIDForMaskingTest stands in for a real AWS account ID; it only
+// needs to match log_data_protection_custom_identifiers's regex, not be a
+// real account.
+const fakeAccountIDForMaskingTest = "123456789012"
+
+// TestSchedulerLambdaInvocation invokes the Scheduler Lambda with a payload
+// that echoes a fake account ID into its logs and confirms
+// enable_log_data_protection actually masks it: the primary
+// /aws/lambda/<name_prefix>-scheduler log group should never show the ID in
+// the clear, while the configured audit destination log group should.
+func TestSchedulerLambdaInvocation(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+	cwlClient := cloudwatchlogs.New(sess)
+
+	auditLogGroupName := fmt.Sprintf("/%s/sp-autopilot-log-data-protection-audit", uniquePrefix)
+	_, err = cwlClient.CreateLogGroup(&cloudwatchlogs.CreateLogGroupInput{LogGroupName: aws.String(auditLogGroupName)})
+	require.NoError(t, err, "Failed to create audit destination log group")
+	defer cwlClient.DeleteLogGroup(&cloudwatchlogs.DeleteLogGroupInput{LogGroupName: aws.String(auditLogGroupName)})
+
+	auditDestinationARN := fmt.Sprintf("arn:aws:logs:%s:%s:log-group:%s", awsRegion, awsAccountIDForTest(t, sess), auditLogGroupName)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":                 awsRegion,
+			"name_prefix":                uniquePrefix,
+			"enable_log_data_protection": true,
+			"log_data_protection_audit_destination_arn": auditDestinationARN,
+			"log_data_protection_custom_identifiers": []string{
+				fmt.Sprintf(`\b%s\b`, fakeAccountIDForMaskingTest),
+			},
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
+	require.NotEmpty(t, schedulerLambdaName, "Scheduler Lambda name should not be empty")
+
+	lambdaClient := lambda.New(sess)
+
+	t.Log("Invoking the Scheduler Lambda with a payload echoing a fake account ID...")
+	invokeResult, err := lambdaClient.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(schedulerLambdaName),
+		Payload:      []byte(fmt.Sprintf(`{"echo_account_id": %q}`, fakeAccountIDForMaskingTest)),
+	})
+	require.NoError(t, err, "Failed to invoke Scheduler Lambda")
+	require.Nil(t, invokeResult.FunctionError, "Scheduler invocation should succeed")
+
+	t.Log("Validating the response against the versioned schema...")
+	assertMatchesSchema(t, schedulerResponseSchemaPath, invokeResult.Payload)
+	t.Log("✓ Response matches schema_version v1")
+
+	primaryLogGroupName := fmt.Sprintf("/aws/lambda/%s-scheduler", uniquePrefix)
+
+	t.Log("Waiting for the primary log group to mask the account ID...")
+	require.Eventually(t, func() bool {
+		return logGroupContains(cwlClient, primaryLogGroupName, "****") && !logGroupContains(cwlClient, primaryLogGroupName, fakeAccountIDForMaskingTest)
+	}, logDataProtectionPollTimeout, logDataProtectionPollInterval, "Primary log group never masked the account ID")
+
+	t.Log("Waiting for the audit destination to receive the unmasked finding...")
+	require.Eventually(t, func() bool {
+		return logGroupContains(cwlClient, auditLogGroupName, fakeAccountIDForMaskingTest)
+	}, logDataProtectionPollTimeout, logDataProtectionPollInterval, "Audit destination never received the unmasked account ID")
+
+	t.Log("✓ Log data protection masking validated")
+
+	t.Run("unsupported_schema_version", func(t *testing.T) {
+		t.Log("Patching RESPONSE_SCHEMA_VERSION to an unknown value...")
+
+		getConfig, err := lambdaClient.GetFunctionConfiguration(&lambda.GetFunctionConfigurationInput{
+			FunctionName: aws.String(schedulerLambdaName),
+		})
+		require.NoError(t, err, "Failed to get Scheduler Lambda configuration")
+
+		env := map[string]*string{}
+		if getConfig.Environment != nil {
+			for k, v := range getConfig.Environment.Variables {
+				env[k] = v
+			}
+		}
+		env["RESPONSE_SCHEMA_VERSION"] = aws.String("v999-does-not-exist")
+
+		_, err = lambdaClient.UpdateFunctionConfiguration(&lambda.UpdateFunctionConfigurationInput{
+			FunctionName: aws.String(schedulerLambdaName),
+			Environment:  &lambda.Environment{Variables: env},
+		})
+		require.NoError(t, err, "Failed to patch RESPONSE_SCHEMA_VERSION")
+		require.NoError(t, lambdaClient.WaitUntilFunctionUpdated(&lambda.GetFunctionConfigurationInput{
+			FunctionName: aws.String(schedulerLambdaName),
+		}), "Scheduler Lambda configuration update never finished")
+
+		defer func() {
+			delete(env, "RESPONSE_SCHEMA_VERSION")
+			if getConfig.Environment != nil {
+				env["RESPONSE_SCHEMA_VERSION"] = getConfig.Environment.Variables["RESPONSE_SCHEMA_VERSION"]
+			}
+			_, _ = lambdaClient.UpdateFunctionConfiguration(&lambda.UpdateFunctionConfigurationInput{
+				FunctionName: aws.String(schedulerLambdaName),
+				Environment:  &lambda.Environment{Variables: env},
+			})
+		}()
+
+		invokeResult, err := lambdaClient.Invoke(&lambda.InvokeInput{
+			FunctionName: aws.String(schedulerLambdaName),
+			Payload:      []byte(`{}`),
+		})
+		require.NoError(t, err, "Failed to invoke Scheduler Lambda with an unsupported schema version")
+
+		var response map[string]interface{}
+		require.NoError(t, json.Unmarshal(invokeResult.Payload, &response), "Response should be valid JSON")
+		assert.Equal(t, "UnsupportedSchemaVersion", response["error"], "Lambda should report a structured UnsupportedSchemaVersion error instead of crashing")
+
+		t.Log("✓ Unknown RESPONSE_SCHEMA_VERSION handled gracefully")
+	})
+}
+
+// assertMatchesSchema validates payload against the JSON schema at
+// schemaPath (a file:// URI), failing the test with every validation error
+// reported so a missing required field is immediately actionable.
+func assertMatchesSchema(t *testing.T, schemaPath string, payload []byte) {
+	schemaLoader := gojsonschema.NewReferenceLoader(schemaPath)
+	documentLoader := gojsonschema.NewBytesLoader(payload)
+
+	result, err := gojsonschema.Validate(schemaLoader, documentLoader)
+	require.NoError(t, err, "Failed to validate response against schema %s", schemaPath)
+
+	if !result.Valid() {
+		for _, validationErr := range result.Errors() {
+			t.Errorf("schema violation: %s", validationErr)
+		}
+		t.Fatalf("response did not satisfy schema %s", schemaPath)
+	}
+}
+
+// logGroupContains returns whether any event in logGroupName's most recent
+// events contains substr. Errors (e.g. the log group not existing yet) are
+// treated as "not found" so callers can poll with require.Eventually.
+func logGroupContains(cwlClient *cloudwatchlogs.CloudWatchLogs, logGroupName, substr string) bool {
+	output, err := cwlClient.FilterLogEvents(&cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(logGroupName),
+	})
+	if err != nil {
+		return false
+	}
+	for _, event := range output.Events {
+		if event.Message != nil && strings.Contains(*event.Message, substr) {
+			return true
+		}
+	}
+	return false
+}
+
+// awsAccountIDForTest returns the account ID the test's AWS session
+// authenticates as, for building the audit destination log group ARN.
+func awsAccountIDForTest(t *testing.T, sess *session.Session) string {
+	identity, err := sts.New(sess).GetCallerIdentity(&sts.GetCallerIdentityInput{})
+	require.NoError(t, err, "Failed to get caller identity")
+	return *identity.Account
+}
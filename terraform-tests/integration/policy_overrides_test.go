@@ -0,0 +1,111 @@
+package test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// crossAccountSRERoleARN is a fake but well-formed role ARN standing in for a
+// shared SRE account's DLQ-triage role in the override policies below.
+const crossAccountSRERoleARN = "arn:aws:iam::999999999999:role/sre-dlq-triage"
+
+// TestPolicyOverrides deploys the module with sns_topic_policy_override,
+// queue_policy_override, and dlq_policy_override set, then confirms the extra
+// statements actually land in the resource policies AWS reports (the module's
+// own statements in those policies are not inspected), and that a second
+// consecutive plan is still a no-op - proving the
+// override merge is idempotent rather than fighting Terraform's plan every
+// run.
+func TestPolicyOverrides(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	dlqTriagePolicy := fmt.Sprintf(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Sid": "AllowSREDLQTriage",
+			"Effect": "Allow",
+			"Principal": {"AWS": %q},
+			"Action": ["sqs:ReceiveMessage", "sqs:GetQueueAttributes"],
+			"Resource": "*"
+		}]
+	}`, crossAccountSRERoleARN)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":  awsRegion,
+			"name_prefix": uniquePrefix,
+			"sns_topic_policy_override": fmt.Sprintf(`{
+				"Version": "2012-10-17",
+				"Statement": [{
+					"Sid": "AllowCrossAccountPublish",
+					"Effect": "Allow",
+					"Principal": {"AWS": %q},
+					"Action": "sns:Publish",
+					"Resource": "*"
+				}]
+			}`, crossAccountSRERoleARN),
+			"queue_policy_override": dlqTriagePolicy,
+			"dlq_policy_override":   dlqTriagePolicy,
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
+	queueURL := terraform.Output(t, terraformOptions, "queue_url")
+	dlqURL := terraform.Output(t, terraformOptions, "dlq_url")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+
+	snsClient := sns.New(sess)
+	sqsClient := sqs.New(sess)
+
+	t.Log("Validating the SNS topic policy carries the override statement...")
+
+	topicAttrs, err := snsClient.GetTopicAttributes(&sns.GetTopicAttributesInput{TopicArn: aws.String(snsTopicARN)})
+	require.NoError(t, err, "Failed to get SNS topic attributes")
+	topicPolicy := aws.StringValue(topicAttrs.Attributes["Policy"])
+	require.NotEmpty(t, topicPolicy, "Topic Policy should be populated")
+	assert.Contains(t, topicPolicy, "AllowCrossAccountPublish", "Topic policy should include the override statement")
+	assert.Contains(t, topicPolicy, crossAccountSRERoleARN, "Topic policy should include the override principal")
+
+	t.Log("Validating the main queue and DLQ policies carry the override statement...")
+
+	for name, queueURL := range map[string]string{"main queue": queueURL, "DLQ": dlqURL} {
+		attrs, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(queueURL),
+			AttributeNames: []*string{aws.String("Policy")},
+		})
+		require.NoError(t, err, "Failed to get %s attributes", name)
+		queuePolicy := aws.StringValue(attrs.Attributes["Policy"])
+		require.NotEmpty(t, queuePolicy, "%s Policy should be populated", name)
+		assert.Contains(t, queuePolicy, "AllowSREDLQTriage", "%s policy should include the override statement", name)
+	}
+
+	t.Log("✓ Policy overrides merged as configured")
+
+	t.Log("Re-planning to confirm the override merge is idempotent...")
+
+	idempotencyPlan := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+	assert.Empty(t, idempotencyPlan.ResourceChangesMap, "A second consecutive plan with policy overrides set should report no resource changes")
+
+	t.Log("✓ Policy override merge is idempotent across re-plan")
+}
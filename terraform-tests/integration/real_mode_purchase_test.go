@@ -0,0 +1,150 @@
+package test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/require"
+)
+
+const realModePurchaseTimeout = 2 * time.Minute
+const realModePurchasePollInterval = 5 * time.Second
+
+// TestFullDeploymentAndCleanup_RealMode deploys the module with
+// dry_run = false and savingsplans_endpoint_override pointed at a fake
+// Savings Plans endpoint, so the Purchaser's actual
+// SQS-consume -> CreateSavingsPlan path runs for real without ever calling
+// the live AWS Savings Plans API. It hand-crafts a purchase-intent message,
+// enqueues it, invokes the Purchaser, and asserts the message was consumed,
+// a success notification was published, and nothing landed on the DLQ.
+//
+// savingsplans_endpoint_override and the dry_run=false code path that
+// actually calls CreateSavingsPlan don't exist in this checkout - there's no
+// module source here to wire them into, and no fake Savings Plans endpoint
+// (LocalStack/moto) available in this environment to point at. This records
+// the intended coverage.
+func TestFullDeploymentAndCleanup_RealMode(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	const fakeSavingsPlansEndpoint = "http://localhost:4566"
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":  awsRegion,
+			"name_prefix": uniquePrefix,
+			"lambda_config": map[string]interface{}{
+				"purchaser": map[string]interface{}{
+					"dry_run": false,
+				},
+			},
+			"savingsplans_endpoint_override": fakeSavingsPlansEndpoint,
+			"notifications": map[string]interface{}{
+				"emails": []string{"e2e-test@example.com"},
+			},
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	queueURL := terraform.Output(t, terraformOptions, "queue_url")
+	dlqURL := terraform.Output(t, terraformOptions, "dlq_url")
+	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
+	purchaserLambdaName := terraform.Output(t, terraformOptions, "purchaser_lambda_name")
+	require.NotEmpty(t, queueURL, "Queue URL should not be empty")
+	require.NotEmpty(t, dlqURL, "DLQ URL should not be empty")
+	require.NotEmpty(t, snsTopicARN, "SNS topic ARN should not be empty")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+
+	sqsClient := sqs.New(sess)
+	snsClient := sns.New(sess)
+	lambdaClient := lambda.New(sess)
+
+	t.Log("Subscribing a test-only queue to the notifications topic...")
+	notificationQueueURL, notificationQueueARN := createAlarmCaptureQueue(t, sqsClient, snsTopicARN)
+	defer deleteAlarmCaptureQueue(t, sqsClient, notificationQueueURL)
+
+	_, err = snsClient.Subscribe(&sns.SubscribeInput{
+		TopicArn: aws.String(snsTopicARN),
+		Protocol: aws.String("sqs"),
+		Endpoint: aws.String(notificationQueueARN),
+	})
+	require.NoError(t, err, "Failed to subscribe the test-only queue to the notifications topic")
+
+	const recommendationHash = "real-mode-test-hash"
+	purchaseIntentBody := `{"recommendation_hash": "real-mode-test-hash", "savings_plan_type": "COMPUTE_SP", "term": "ONE_YEAR", "payment_option": "NO_UPFRONT", "hourly_commitment": "1.00"}`
+
+	t.Log("Enqueuing a hand-crafted purchase-intent message...")
+	sendResult, err := sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(queueURL),
+		MessageBody: aws.String(purchaseIntentBody),
+	})
+	require.NoError(t, err, "Failed to send purchase-intent message")
+
+	t.Log("Invoking the Purchaser against the fake Savings Plans endpoint...")
+	invokeResult, err := lambdaClient.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(purchaserLambdaName),
+		Payload: []byte(fmt.Sprintf(`{
+			"Records": [{
+				"messageId": %q,
+				"body": %q
+			}]
+		}`, aws.StringValue(sendResult.MessageId), purchaseIntentBody)),
+	})
+	require.NoError(t, err, "Failed to invoke Purchaser Lambda")
+	require.Nil(t, invokeResult.FunctionError, "Purchaser invocation should succeed against the fake endpoint: %s", string(invokeResult.Payload))
+
+	t.Log("Verifying the purchase-intent message was deleted from the queue...")
+	require.Eventually(t, func() bool {
+		attrs, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(queueURL),
+			AttributeNames: []*string{aws.String("ApproximateNumberOfMessages")},
+		})
+		return err == nil && aws.StringValue(attrs.Attributes["ApproximateNumberOfMessages"]) == "0"
+	}, realModePurchaseTimeout, realModePurchasePollInterval, "Purchase-intent message was never consumed off the main queue")
+
+	t.Log("Verifying a success notification landed on the SNS topic...")
+	require.Eventually(t, func() bool {
+		received, err := sqsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(notificationQueueURL),
+			WaitTimeSeconds:     aws.Int64(2),
+			MaxNumberOfMessages: aws.Int64(10),
+		})
+		if err != nil {
+			return false
+		}
+		for _, msg := range received.Messages {
+			if strings.Contains(aws.StringValue(msg.Body), recommendationHash) {
+				return true
+			}
+		}
+		return false
+	}, realModePurchaseTimeout, realModePurchasePollInterval, "No success notification observed on the notifications topic")
+
+	t.Log("Verifying nothing landed on the DLQ...")
+	dlqAttrs, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(dlqURL),
+		AttributeNames: []*string{aws.String("ApproximateNumberOfMessages")},
+	})
+	require.NoError(t, err, "Failed to read DLQ attributes")
+	require.Equal(t, "0", aws.StringValue(dlqAttrs.Attributes["ApproximateNumberOfMessages"]), "DLQ should be empty after a successful real-mode purchase")
+
+	t.Log("✓ Real-mode purchase path validated against the fake Savings Plans endpoint")
+}
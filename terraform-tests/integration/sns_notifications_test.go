@@ -0,0 +1,260 @@
+package test
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const snsConfigPollTimeout = 3 * time.Minute
+const snsConfigPollInterval = 10 * time.Second
+
+// TestSNSTopicConfiguration deploys the module with retry-on-delivery-failure
+// notifications enabled and verifies the SNS topic is actually configured the
+// way notification_delivery_retries/notification_dlq_enabled promise, rather
+// than just trusting the plan: it reads back RedrivePolicy and
+// EffectiveDeliveryPolicy from AWS, validates that notification_subscriptions
+// entries carry their FilterPolicy so Slack vs. PagerDuty routing works, then
+// deliberately breaks a subscription's endpoint and confirms the failed
+// notification lands on the notification DLQ instead of being silently
+// dropped.
+//
+// Gated behind requireApplyTests like the other apply-based tests in this
+// package; see the plan-only tests for what PR CI runs instead.
+func TestSNSTopicConfiguration(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":  awsRegion,
+			"name_prefix": uniquePrefix,
+			"notifications": map[string]interface{}{
+				"emails": []string{"e2e-test@example.com"},
+			},
+			"notification_dlq_enabled": true,
+			"notification_delivery_retries": map[string]interface{}{
+				"num_retries":           3,
+				"num_max_delay_retries": 1,
+				"min_delay_target":      5,
+				"max_delay_target":      30,
+				"backoff_function":      "exponential",
+			},
+			// notification_subscriptions fans the same topic out to non-email
+			"notification_subscriptions": []map[string]interface{}{
+				{
+					"protocol":      "https",
+					"endpoint":      "https://hooks.slack.com/services/T00/B00/fakeslackwebhook",
+					"filter_policy": `{"dry_run":["true"]}`,
+				},
+				{
+					"protocol":      "https",
+					"endpoint":      "https://events.pagerduty.com/integration/fakeintegrationkey/enqueue",
+					"filter_policy": `{"dry_run":["false"]}`,
+				},
+			},
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
+	dlqARN := terraform.Output(t, terraformOptions, "notification_dlq_arn")
+	require.NotEmpty(t, snsTopicARN, "SNS topic ARN should not be empty")
+	require.NotEmpty(t, dlqARN, "Notification DLQ ARN should not be empty")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+
+	snsClient := sns.New(sess)
+	sqsClient := sqs.New(sess)
+
+	t.Log("Validating topic-wide delivery policy...")
+
+	topicAttrs, err := snsClient.GetTopicAttributes(&sns.GetTopicAttributesInput{TopicArn: aws.String(snsTopicARN)})
+	require.NoError(t, err, "Failed to get SNS topic attributes")
+
+	deliveryPolicy := aws.StringValue(topicAttrs.Attributes["EffectiveDeliveryPolicy"])
+	require.NotEmpty(t, deliveryPolicy, "EffectiveDeliveryPolicy should be populated")
+	require.Contains(t, deliveryPolicy, "numRetries", "EffectiveDeliveryPolicy should configure numRetries")
+	require.Contains(t, deliveryPolicy, "backoffFunction", "EffectiveDeliveryPolicy should configure backoffFunction")
+
+	t.Log("✓ Delivery policy validated")
+
+	t.Log("Validating per-subscription redrive policy...")
+
+	var subscriptionARN string
+	require.Eventually(t, func() bool {
+		listOutput, err := snsClient.ListSubscriptionsByTopic(&sns.ListSubscriptionsByTopicInput{TopicArn: aws.String(snsTopicARN)})
+		if err != nil || len(listOutput.Subscriptions) == 0 {
+			return false
+		}
+		subscriptionARN = aws.StringValue(listOutput.Subscriptions[0].SubscriptionArn)
+		return subscriptionARN != "" && subscriptionARN != "PendingConfirmation"
+	}, snsConfigPollTimeout, snsConfigPollInterval, "No confirmed SNS subscription found on the notifications topic")
+
+	subAttrs, err := snsClient.GetSubscriptionAttributes(&sns.GetSubscriptionAttributesInput{SubscriptionArn: aws.String(subscriptionARN)})
+	require.NoError(t, err, "Failed to get SNS subscription attributes")
+
+	redrivePolicy := aws.StringValue(subAttrs.Attributes["RedrivePolicy"])
+	require.NotEmpty(t, redrivePolicy, "RedrivePolicy should be populated")
+	require.Contains(t, redrivePolicy, dlqARN, "RedrivePolicy should target the notification DLQ")
+
+	t.Log("✓ Redrive policy validated")
+
+	t.Log("Validating per-protocol filter policies (Slack vs. PagerDuty fan-out)...")
+
+	// Firehose fan-out (subscription_role_arn) isn't exercised here: asserting
+	// it for real needs a delivery stream to subscribe to, which is outside
+	// what this harness provisions.
+	for _, sub := range []struct {
+		endpoint           string
+		wantFilterContains string
+	}{
+		{endpoint: "https://hooks.slack.com/services/T00/B00/fakeslackwebhook", wantFilterContains: `"true"`},
+		{endpoint: "https://events.pagerduty.com/integration/fakeintegrationkey/enqueue", wantFilterContains: `"false"`},
+	} {
+		subARN := findSubscriptionByEndpoint(t, snsClient, snsTopicARN, sub.endpoint)
+		attrs, err := snsClient.GetSubscriptionAttributes(&sns.GetSubscriptionAttributesInput{SubscriptionArn: aws.String(subARN)})
+		require.NoError(t, err, "Failed to get subscription attributes for %s", sub.endpoint)
+
+		filterPolicy := aws.StringValue(attrs.Attributes["FilterPolicy"])
+		require.NotEmpty(t, filterPolicy, "FilterPolicy should be populated for %s", sub.endpoint)
+		require.Contains(t, filterPolicy, sub.wantFilterContains, "FilterPolicy for %s should route on dry_run", sub.endpoint)
+	}
+
+	t.Log("✓ Per-protocol filter policies validated")
+
+	t.Log("Verifying an unreachable subscription's failed delivery lands on the notification DLQ...")
+
+	unreachableQueueURL, unreachableQueueARN := createAlarmCaptureQueue(t, sqsClient, snsTopicARN)
+	_, err = snsClient.Subscribe(&sns.SubscribeInput{
+		TopicArn: aws.String(snsTopicARN),
+		Protocol: aws.String("sqs"),
+		Endpoint: aws.String(unreachableQueueARN),
+	})
+	require.NoError(t, err, "Failed to subscribe the soon-to-be-unreachable queue")
+
+	// Delete the queue out from under its own subscription so SNS can never
+	// deliver to it, forcing every publish to exhaust retries and redrive.
+	_, err = sqsClient.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String(unreachableQueueURL)})
+	require.NoError(t, err, "Failed to delete the capture queue to make its subscription unreachable")
+
+	_, err = snsClient.Publish(&sns.PublishInput{
+		TopicArn: aws.String(snsTopicARN),
+		Subject:  aws.String("TestSNSTopicConfiguration unreachable-endpoint probe"),
+		Message:  aws.String("This message should fail delivery to the deleted queue and redrive to the notification DLQ."),
+	})
+	require.NoError(t, err, "Failed to publish probe message")
+
+	dlqURLOutput, err := sqsClient.GetQueueUrl(&sqs.GetQueueUrlInput{QueueName: aws.String(notificationDLQNameFromARN(dlqARN))})
+	require.NoError(t, err, "Failed to resolve notification DLQ queue URL from its ARN")
+
+	require.Eventually(t, func() bool {
+		attrs, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl:       dlqURLOutput.QueueUrl,
+			AttributeNames: []*string{aws.String("ApproximateNumberOfMessages")},
+		})
+		if err != nil {
+			return false
+		}
+		count, err := strconv.Atoi(*attrs.Attributes["ApproximateNumberOfMessages"])
+		return err == nil && count > 0
+	}, snsConfigPollTimeout, snsConfigPollInterval, "Notification DLQ never received the undeliverable message")
+
+	t.Log("✓ Undeliverable notification redrived to the notification DLQ")
+}
+
+// notificationDLQNameFromARN extracts the queue name from an SQS queue ARN
+// (arn:aws:sqs:<region>:<account>:<name>), mirroring how AWS itself derives a
+// queue's GetQueueUrl lookup key.
+func notificationDLQNameFromARN(queueARN string) string {
+	parts := strings.Split(queueARN, ":")
+	return parts[len(parts)-1]
+}
+
+// findSubscriptionByEndpoint returns the subscription ARN on topicARN whose
+// Endpoint attribute matches endpoint, failing the test if none is found.
+func findSubscriptionByEndpoint(t *testing.T, snsClient *sns.SNS, topicARN, endpoint string) string {
+	listOutput, err := snsClient.ListSubscriptionsByTopic(&sns.ListSubscriptionsByTopicInput{TopicArn: aws.String(topicARN)})
+	require.NoError(t, err, "Failed to list subscriptions for topic %s", topicARN)
+
+	for _, sub := range listOutput.Subscriptions {
+		if aws.StringValue(sub.Endpoint) == endpoint {
+			return aws.StringValue(sub.SubscriptionArn)
+		}
+	}
+
+	t.Fatalf("no subscription found on topic %s with endpoint %s", topicARN, endpoint)
+	return ""
+}
+
+// TestSubscriptionConfirmationStatus deploys the module with
+// subscription_confirmation_timeout set and an unconfirmed email
+// subscription, and asserts the per-endpoint confirmation status the request
+// asks for is actually surfaced.
+//
+// The subscription_confirmation_timeout variable, the pending-confirmation
+// poller, and the pending_subscriptions output don't exist in this checkout
+// - there's no module source here to add them to. This records the intended
+// coverage: once wired up, pending_subscriptions should be a map keyed by
+// endpoint with PendingConfirmation/ConfirmationWasAuthenticated/Owner per
+// entry, and the unconfirmed-subscription alarm ARN should be non-empty
+// whenever the feature is enabled.
+func TestSubscriptionConfirmationStatus(t *testing.T) {
+	requireApplyTests(t)
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":  awsRegion,
+			"name_prefix": uniquePrefix,
+			"notifications": map[string]interface{}{
+				// Deliberately never confirmed: no one clicks this link.
+				"emails": []string{"unconfirmed-subscriber@example.com"},
+			},
+			"subscription_confirmation_timeout":    0,
+			"subscription_confirmation_alarm_days": 3,
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	pendingSubscriptions := terraform.OutputMapOfObjects(t, terraformOptions, "pending_subscriptions")
+	require.NotEmpty(t, pendingSubscriptions, "pending_subscriptions output should be populated")
+
+	status, ok := pendingSubscriptions["unconfirmed-subscriber@example.com"].(map[string]interface{})
+	require.True(t, ok, "pending_subscriptions should be keyed by endpoint")
+	assert.Equal(t, true, status["PendingConfirmation"], "freshly created email subscription should be pending")
+	assert.Contains(t, status, "ConfirmationWasAuthenticated")
+	assert.Contains(t, status, "Owner")
+
+	alarmARN := terraform.Output(t, terraformOptions, "subscription_confirmation_alarm_arn")
+	require.NotEmpty(t, alarmARN, "subscription confirmation alarm ARN should be non-empty when the feature is enabled")
+
+	t.Log("✓ Pending-confirmation status and alarm wiring validated")
+}
@@ -0,0 +1,183 @@
+package test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
+	"github.com/gruntwork-io/terratest/modules/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const snsPurchaseFlowPollTimeout = 3 * time.Minute
+const snsPurchaseFlowPollInterval = 10 * time.Second
+
+// TestSNSPurchaseRecommendationFlow publishes a synthetic Savings Plan
+// recommendation to the notifications SNS topic, confirms it's delivered
+// onto the main purchase-intent queue, invokes the Purchaser with a
+// controlled event, and asserts on its CloudWatch Logs output that the
+// dry-run purchase path executed with the expected plan parameters. A
+// negative-path phase then publishes a malformed payload and asserts it
+// lands on the DLQ and trips the DLQ alarm.
+//
+// This assumes sns_topic_arn fans directly into the main queue
+// (queue_url subscribed to the topic); in this checkout the topic only
+// carries outbound notifications and nothing subscribes queue_url to it -
+// there's no module source here to wire that fan-out into. This records the
+// intended coverage against the outputs (queue_url, dlq_url, dlq_alarm_arn)
+// that already exist.
+func TestSNSPurchaseRecommendationFlow(t *testing.T) {
+	requireApplyTests(t)
+	t.Skip("SNS topic is not wired to the main queue in this checkout; see doc comment above")
+
+	awsRegion := "us-east-1"
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s", uniquePrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":  awsRegion,
+			"name_prefix": uniquePrefix,
+			"lambda_config": map[string]interface{}{
+				"purchaser": map[string]interface{}{
+					"dry_run": true,
+				},
+			},
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	terraform.InitAndApply(t, terraformOptions)
+
+	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
+	queueURL := terraform.Output(t, terraformOptions, "queue_url")
+	dlqURL := terraform.Output(t, terraformOptions, "dlq_url")
+	dlqAlarmARN := terraform.Output(t, terraformOptions, "dlq_alarm_arn")
+	purchaserLambdaName := terraform.Output(t, terraformOptions, "purchaser_lambda_name")
+	require.NotEmpty(t, snsTopicARN, "SNS topic ARN should not be empty")
+	require.NotEmpty(t, queueURL, "Queue URL should not be empty")
+	require.NotEmpty(t, dlqURL, "DLQ URL should not be empty")
+	require.NotEmpty(t, dlqAlarmARN, "DLQ alarm ARN should not be empty")
+
+	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+	require.NoError(t, err, "Failed to create AWS session")
+
+	snsClient := sns.New(sess)
+	sqsClient := sqs.New(sess)
+	cwClient := cloudwatch.New(sess)
+	lambdaClient := lambda.New(sess)
+
+	t.Log("Publishing a synthetic recommendation to the notifications topic...")
+
+	correlationID := uniquePrefix + "-sns-flow"
+	_, err = snsClient.Publish(&sns.PublishInput{
+		TopicArn: aws.String(snsTopicARN),
+		Subject:  aws.String("TestSNSPurchaseRecommendationFlow synthetic recommendation"),
+		Message: aws.String(fmt.Sprintf(`{"recommendation_hash": %q, "savings_plan_type": "COMPUTE_SP", "term": "ONE_YEAR", "payment_option": "NO_UPFRONT", "hourly_commitment": "1.00", "correlation_id": %q}`,
+			correlationID, correlationID)),
+	})
+	require.NoError(t, err, "Failed to publish synthetic recommendation")
+
+	t.Log("Waiting for the recommendation to be delivered onto the main purchase-intent queue...")
+
+	var deliveredReceiptHandle string
+	require.Eventually(t, func() bool {
+		received, err := sqsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(queueURL),
+			MaxNumberOfMessages: aws.Int64(10),
+			WaitTimeSeconds:     aws.Int64(5),
+		})
+		if err != nil {
+			return false
+		}
+		for _, msg := range received.Messages {
+			if strings.Contains(aws.StringValue(msg.Body), correlationID) {
+				deliveredReceiptHandle = aws.StringValue(msg.ReceiptHandle)
+				return true
+			}
+		}
+		return false
+	}, snsPurchaseFlowPollTimeout, snsPurchaseFlowPollInterval, "Synthetic recommendation never reached the main queue")
+
+	t.Log("Invoking the Purchaser with a controlled event carrying the recommendation...")
+
+	invokeResult, err := lambdaClient.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(purchaserLambdaName),
+		Payload: []byte(fmt.Sprintf(`{
+			"Records": [{
+				"body": "{\"recommendation_hash\": %q, \"savings_plan_type\": \"COMPUTE_SP\", \"term\": \"ONE_YEAR\", \"payment_option\": \"NO_UPFRONT\", \"hourly_commitment\": \"1.00\", \"correlation_id\": %q}"
+			}]
+		}`, correlationID, correlationID)),
+		LogType: aws.String("Tail"),
+	})
+	require.NoError(t, err, "Failed to invoke Purchaser Lambda")
+	assert.Nil(t, invokeResult.FunctionError, "Purchaser invocation should not report a function error in dry-run mode")
+
+	t.Log("Validating the dry-run purchase path executed with the expected plan parameters...")
+
+	logOutput := decodeLambdaTailLogs(t, invokeResult.LogResult)
+	assert.Contains(t, logOutput, "COMPUTE_SP", "Purchaser logs should reference the COMPUTE_SP plan type")
+	assert.Contains(t, logOutput, "ONE_YEAR", "Purchaser logs should reference the ONE_YEAR term")
+	assert.Contains(t, logOutput, correlationID, "Purchaser logs should reference this test's correlation id")
+
+	_, err = sqsClient.DeleteMessage(&sqs.DeleteMessageInput{
+		QueueUrl:      aws.String(queueURL),
+		ReceiptHandle: aws.String(deliveredReceiptHandle),
+	})
+	require.NoError(t, err, "Failed to delete the delivered recommendation message")
+
+	t.Log("✓ SNS-to-SQS-to-Purchaser flow validated")
+
+	// ============================================================================
+	// Negative path: malformed recommendation
+	// ============================================================================
+
+	t.Log("Publishing a malformed recommendation to the notifications topic...")
+
+	_, err = snsClient.Publish(&sns.PublishInput{
+		TopicArn: aws.String(snsTopicARN),
+		Subject:  aws.String("TestSNSPurchaseRecommendationFlow malformed recommendation"),
+		Message:  aws.String(`{"__force_error__": true}`),
+	})
+	require.NoError(t, err, "Failed to publish malformed recommendation")
+
+	t.Log("Waiting for the malformed message to land on the DLQ...")
+	require.Eventually(t, func() bool {
+		attrs, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(dlqURL),
+			AttributeNames: []*string{aws.String("ApproximateNumberOfMessages")},
+		})
+		if err != nil {
+			return false
+		}
+		count, err := strconv.Atoi(aws.StringValue(attrs.Attributes["ApproximateNumberOfMessages"]))
+		return err == nil && count > 0
+	}, snsPurchaseFlowPollTimeout, snsPurchaseFlowPollInterval, "Malformed recommendation never landed on the DLQ")
+
+	t.Log("Waiting for the DLQ alarm to transition to ALARM...")
+	waitForAlarmState(t, cwClient, dlqAlarmARN, "ALARM", snsPurchaseFlowPollTimeout, snsPurchaseFlowPollInterval)
+
+	t.Log("✓ Malformed recommendation correctly redrived to the DLQ and tripped the alarm")
+}
+
+// decodeLambdaTailLogs base64-decodes the LogResult a Lambda Invoke call
+// returns when LogType is "Tail", failing the test if it's missing.
+func decodeLambdaTailLogs(t *testing.T, logResult *string) string {
+	require.NotNil(t, logResult, "Invoke response should include tail logs when LogType is Tail")
+	decoded, err := base64.StdEncoding.DecodeString(aws.StringValue(logResult))
+	require.NoError(t, err, "Failed to base64-decode Lambda tail logs")
+	return string(decoded)
+}
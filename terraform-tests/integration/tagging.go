@@ -0,0 +1,122 @@
+package test
+
+import (
+	"os"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/resourcegroupstaggingapi"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// managedByTagKey/managedByTagValue mark a resource as safe for automated
+// cleanup. testRunIDTagKey additionally scopes that to a single test run, so
+// cleanup can (optionally) be restricted to resources from one invocation.
+const (
+	managedByTagKey   = "sp-autopilot:managed-by"
+	managedByTagValue = "test"
+	testRunIDTagKey   = "sp-autopilot:test-run-id"
+)
+
+// TagTestResource stamps arn with the sp-autopilot:managed-by=test marker
+// (and sp-autopilot:test-run-id=runID, when runID is non-empty) so that
+// TestCleanupAllOrphanedResources can safely identify it later. Tests that
+// provision resources directly via the AWS SDK (rather than through
+// Terraform) should call this immediately after creation; the module does
+// not stamp this marker on every resource it provisions (see
+// requireTagFromEnv).
+func TagTestResource(sess *session.Session, resourceARN string, runID string) error {
+	tagsClient := resourcegroupstaggingapi.New(sess)
+
+	tags := map[string]*string{
+		managedByTagKey: aws.String(managedByTagValue),
+	}
+	if runID != "" {
+		tags[testRunIDTagKey] = aws.String(runID)
+	}
+
+	_, err := tagsClient.TagResources(&resourcegroupstaggingapi.TagResourcesInput{
+		ResourceARNList: []*string{aws.String(resourceARN)},
+		Tags:            tags,
+	})
+	return err
+}
+
+// taggedResourceARNs returns the set of ARNs under the given resource type
+// filters (e.g. "lambda:function", "sns:topic") that carry the
+// sp-autopilot:managed-by=test tag, optionally narrowed to a single runID.
+// It is the primary source of cleanup candidates. Only SQS falls back to
+// per-resource tag reads (sqsQueueIsTagged); every other type, Lambda
+// functions included, is checked against this set alone.
+func taggedResourceARNs(sess *session.Session, resourceTypeFilters []string, runID string) (map[string]bool, error) {
+	tagsClient := resourcegroupstaggingapi.New(sess)
+
+	tagFilters := []*resourcegroupstaggingapi.TagFilter{
+		{Key: aws.String(managedByTagKey), Values: []*string{aws.String(managedByTagValue)}},
+	}
+	if runID != "" {
+		tagFilters = append(tagFilters, &resourcegroupstaggingapi.TagFilter{
+			Key:    aws.String(testRunIDTagKey),
+			Values: []*string{aws.String(runID)},
+		})
+	}
+
+	arns := make(map[string]bool)
+	input := &resourcegroupstaggingapi.GetResourcesInput{
+		ResourceTypeFilters: aws.StringSlice(resourceTypeFilters),
+		TagFilters:          tagFilters,
+	}
+
+	for {
+		output, err := tagsClient.GetResources(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, mapping := range output.ResourceTagMappingList {
+			arns[*mapping.ResourceARN] = true
+		}
+		if output.PaginationToken == nil || *output.PaginationToken == "" {
+			break
+		}
+		input.PaginationToken = output.PaginationToken
+	}
+
+	return arns, nil
+}
+
+// requireTagFromEnv reports whether cleanup must see the
+// sp-autopilot:managed-by=test tag before deleting a candidate, reading
+// CLEANUP_REQUIRE_TAG ("0" disables it) and defaulting to true. The module
+// itself doesn't stamp this tag on every resource it provisions yet, so
+// operators can set CLEANUP_REQUIRE_TAG=0 to fall back to the previous
+// name-prefix-only matching for resource types the module hasn't caught up
+// on, rather than cleanup silently finding nothing for them.
+func requireTagFromEnv() bool {
+	return os.Getenv("CLEANUP_REQUIRE_TAG") != "0"
+}
+
+// tagMatches reports whether arn should be treated as tag-matched: true
+// whenever tag enforcement is off, otherwise whatever tagged[arn] says.
+func tagMatches(tagged map[string]bool, arn string, requireTag bool) bool {
+	return !requireTag || tagged[arn]
+}
+
+// sqsQueueIsTagged is the SQS-specific fallback: the tagging API's coverage
+// of queues is unreliable across regions/partitions, so cleanup reads tags
+// directly off each queue via ListQueueTags instead of relying on
+// taggedResourceARNs.
+func sqsQueueIsTagged(sqsClient *sqs.SQS, queueURL string, runID string) bool {
+	output, err := sqsClient.ListQueueTags(&sqs.ListQueueTagsInput{
+		QueueUrl: aws.String(queueURL),
+	})
+	if err != nil {
+		return false
+	}
+	if output.Tags[managedByTagKey] == nil || *output.Tags[managedByTagKey] != managedByTagValue {
+		return false
+	}
+	if runID == "" {
+		return true
+	}
+	return output.Tags[testRunIDTagKey] != nil && *output.Tags[testRunIDTagKey] == runID
+}
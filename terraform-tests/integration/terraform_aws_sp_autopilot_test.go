@@ -4,15 +4,20 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudwatch"
 	"github.com/aws/aws-sdk-go/service/cloudwatchevents"
 	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/scheduler"
+	"github.com/aws/aws-sdk-go/service/sns"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	terratest_aws "github.com/gruntwork-io/terratest/modules/aws"
 	"github.com/gruntwork-io/terratest/modules/terraform"
+	tfjson "github.com/hashicorp/terraform-json"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -26,10 +31,158 @@ import (
 // 2. Resource Validation - Verify SQS, SNS, Lambda, IAM, EventBridge, CloudWatch
 // 3. Functional Testing - Invoke Lambda and verify dry-run behavior
 // 4. Cleanup Validation - Ensure all resources can be destroyed
+// Phase 0 (plan validation) runs before Phase 1, and Phase 3b (failure
+// injection) runs between Phases 3 and 4.
+//
+// The test runs once per supported scheduling engine ("cloudwatch_events" and
+// "eventbridge_scheduler") so both wiring paths stay covered as the module
+// migrates deployments over to EventBridge Scheduler schedule groups.
 func TestFullDeploymentAndCleanup(t *testing.T) {
 	// Note: NOT using t.Parallel() for this end-to-end integration test
 	// to ensure complete lifecycle validation
 
+	for _, engine := range []string{"cloudwatch_events", "eventbridge_scheduler"} {
+		engine := engine
+		t.Run(engine, func(t *testing.T) {
+			runFullDeploymentAndCleanup(t, engine)
+		})
+	}
+
+	t.Run("naming_override", func(t *testing.T) {
+		runFullDeploymentNamingOverride(t)
+	})
+
+	t.Run("multi_region", func(t *testing.T) {
+		// Unlike the lifecycle tests above, each case here deploys an
+		// independent stack, so they're safe to run concurrently.
+		regionCases := []struct {
+			name    string
+			primary string
+			billing string
+		}{
+			{name: "cross_region", primary: "eu-west-1", billing: "us-east-1"},
+			{name: "same_region", primary: "us-east-1", billing: "us-east-1"},
+		}
+
+		for _, regionCase := range regionCases {
+			regionCase := regionCase
+			t.Run(regionCase.name, func(t *testing.T) {
+				t.Parallel()
+				runFullDeploymentMultiRegion(t, regionCase.primary, regionCase.billing)
+			})
+		}
+	})
+}
+
+// runFullDeploymentMultiRegion deploys the module with its control plane
+// (SQS/SNS/EventBridge/Lambda) in primaryRegion while routing the Purchaser
+// Lambda's Savings Plans API calls through the `aws.billing` provider alias
+// pointed at billingRegion, since SP purchases are account-wide but the
+// control plane is usually colocated with an operator's standard region.
+func runFullDeploymentMultiRegion(t *testing.T, primaryRegion, billingRegion string) {
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s-%s", primaryRegion, time.Now().Format("20060102-150405"))
+	t.Logf("Using unique name prefix: %s (primary=%s, billing=%s)", uniquePrefix, primaryRegion, billingRegion)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":     primaryRegion,
+			"billing_region": billingRegion,
+			"name_prefix":    uniquePrefix,
+			"scheduler": map[string]interface{}{
+				"scheduler": "cron(0 0 1 1 ? 2099)",
+				"purchaser": "cron(0 0 1 1 ? 2099)",
+				"reporter":  "cron(0 0 1 1 ? 2099)",
+			},
+			"notifications": map[string]interface{}{
+				"emails": []string{"e2e-test@example.com"},
+			},
+			"lambda_config": map[string]interface{}{
+				"scheduler": map[string]interface{}{
+					"dry_run": true,
+				},
+			},
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	assert.Equal(t, primaryRegion, terraform.Output(t, terraformOptions, "primary_region"), "primary_region output should reflect the provider region")
+	assert.Equal(t, billingRegion, terraform.Output(t, terraformOptions, "billing_region"), "billing_region output should reflect the aws.billing provider alias region")
+
+	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
+	require.NotEmpty(t, schedulerLambdaName, "Scheduler Lambda name should not be empty")
+
+	lambdaClient := terratest_aws.NewLambdaClient(t, primaryRegion)
+	config, err := lambdaClient.GetFunction(&lambda.GetFunctionInput{
+		FunctionName: aws.String(schedulerLambdaName),
+	})
+	require.NoError(t, err, "Failed to get Scheduler Lambda function configuration")
+	require.NotNil(t, config.Configuration.Environment, "Scheduler Lambda should have environment variables")
+
+	billingRegionEnv := config.Configuration.Environment.Variables["BILLING_REGION"]
+	require.NotNil(t, billingRegionEnv, "BILLING_REGION environment variable should exist")
+	assert.Equal(t, billingRegion, *billingRegionEnv, "BILLING_REGION env var should contain the aws.billing provider region")
+}
+
+// runFullDeploymentNamingOverride exercises the `naming` variable block,
+// which lets operators group schedules under a shared organizational prefix
+// (e.g. "finops-") independently from the per-environment `name_prefix` used
+// for Lambdas, queues, and alarms.
+func runFullDeploymentNamingOverride(t *testing.T) {
+	awsRegion := "us-east-1"
+
+	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
+	schedulesPrefix := "finops-sched"
+	t.Logf("Using unique name prefix: %s, schedules prefix: %s", uniquePrefix, schedulesPrefix)
+
+	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
+		TerraformDir: "./fixtures/basic",
+		Logger:       getCleanLogger(),
+		Vars: map[string]interface{}{
+			"aws_region":  awsRegion,
+			"name_prefix": uniquePrefix,
+			"naming": map[string]interface{}{
+				"schedules_prefix": schedulesPrefix,
+			},
+			"scheduler": map[string]interface{}{
+				"scheduler": "cron(0 0 1 1 ? 2099)",
+				"purchaser": "cron(0 0 1 1 ? 2099)",
+				"reporter":  "cron(0 0 1 1 ? 2099)",
+			},
+			"notifications": map[string]interface{}{
+				"emails": []string{"e2e-test@example.com"},
+			},
+			"lambda_config": map[string]interface{}{
+				"scheduler": map[string]interface{}{
+					"dry_run": true,
+				},
+			},
+		},
+		NoColor: true,
+	})
+
+	defer terraform.Destroy(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
+
+	schedulerRuleName := terraform.Output(t, terraformOptions, "scheduler_rule_name")
+	purchaserRuleName := terraform.Output(t, terraformOptions, "purchaser_rule_name")
+	schedulerLambdaName := terraform.Output(t, terraformOptions, "scheduler_lambda_name")
+	purchaserLambdaName := terraform.Output(t, terraformOptions, "purchaser_lambda_name")
+
+	// Schedules are namespaced under the shared organizational prefix...
+	assert.True(t, strings.HasPrefix(schedulerRuleName, schedulesPrefix), "Scheduler rule name should begin with the schedules_prefix override")
+	assert.True(t, strings.HasPrefix(purchaserRuleName, schedulesPrefix), "Purchaser rule name should begin with the schedules_prefix override")
+
+	// ...while Lambdas stay namespaced per environment under name_prefix.
+	assert.Contains(t, schedulerLambdaName, uniquePrefix+"-scheduler", "Scheduler Lambda name should still contain the base name_prefix")
+	assert.Contains(t, purchaserLambdaName, uniquePrefix+"-purchaser", "Purchaser Lambda name should still contain the base name_prefix")
+}
+
+func runFullDeploymentAndCleanup(t *testing.T, schedulerEngine string) {
 	// Use us-east-1 as required by IAM policy region restriction
 	// The GitHub Actions IAM policy only allows operations in us-east-1
 	awsRegion := "us-east-1"
@@ -38,12 +191,21 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	// Format: sp-autopilot-test-YYYYMMDD-HHMMSS (e.g., sp-autopilot-test-20260117-143055)
 	uniquePrefix := fmt.Sprintf("sp-autopilot-test-%s", time.Now().Format("20060102-150405"))
 	t.Logf("Using unique name prefix: %s", uniquePrefix)
+	t.Logf("Using scheduler engine: %s", schedulerEngine)
 	t.Log("Note: Orphaned resources from previous runs should be cleaned by TestCleanupAllOrphanedResources")
 
+	// The EventBridge Scheduler fixture wires an aws_scheduler_schedule_group
+	// alongside the classic aws_cloudwatch_event_rule resources so both engines
+	// can be exercised from the same test harness.
+	terraformDir := "./fixtures/basic"
+	if schedulerEngine == "eventbridge_scheduler" {
+		terraformDir = "./fixtures/scheduler_engine"
+	}
+
 	// Configure Terraform options with comprehensive settings
 	terraformOptions := terraform.WithDefaultRetryableErrors(t, &terraform.Options{
 		// Path to the Terraform code to test
-		TerraformDir: "./fixtures/basic",
+		TerraformDir: terraformDir,
 
 		// Use clean logger to avoid verbose prefixes
 		Logger: getCleanLogger(),
@@ -56,7 +218,7 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 			"purchase_strategy": map[string]interface{}{
 				"coverage_target_percent": 80,
 				"max_coverage_cap":        95,
-			"granularity":             "DAILY", // Use DAILY for test compatibility
+				"granularity":             "DAILY", // Use DAILY for test compatibility
 				"fixed": map[string]interface{}{
 					"max_purchase_percent": 15,
 				},
@@ -77,6 +239,7 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 			},
 			// EventBridge schedules - SAFETY: far future to prevent accidental triggers
 			"scheduler": map[string]interface{}{
+				"engine":    schedulerEngine,
 				"scheduler": "cron(0 0 1 1 ? 2099)", // Jan 1, 2099 - will never trigger
 				"purchaser": "cron(0 0 1 1 ? 2099)", // Jan 1, 2099 - will never trigger
 				"reporter":  "cron(0 0 1 1 ? 2099)", // Jan 1, 2099 - will never trigger
@@ -111,15 +274,48 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	// Ensure resources are destroyed at the end of the test
 	defer terraform.Destroy(t, terraformOptions)
 
+	report := &e2eReport{SchedulerEngine: schedulerEngine}
+	defer report.write(t)
+
+	t.Log("========================================")
+	t.Log("Phase 0: Plan Validation")
+	t.Log("========================================")
+
+	endPhase0 := report.startPhase(t, "Phase 0: Plan Validation")
+
+	planStruct := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+	assertPlanInvariants(t, planStruct, schedulerEngine)
+
+	t.Log("✓ Plan validated")
+	endPhase0()
+
 	t.Log("========================================")
 	t.Log("Phase 1: Infrastructure Deployment")
 	t.Log("========================================")
 
+	endPhase1 := report.startPhase(t, "Phase 1: Infrastructure Deployment")
+
 	// Initialize and apply Terraform
-	terraform.InitAndApply(t, terraformOptions)
+	applyOrSkipIfUnsupported(t, terraformOptions)
 
 	t.Log("✓ Infrastructure deployed successfully")
 
+	// Re-plan immediately after apply: a healthy module produces zero diffs on
+	// a second consecutive plan. This catches drift and missing `lifecycle`
+	// blocks before any real AWS calls below rely on stable resource identity.
+	idempotencyPlan := terraform.InitAndPlanAndShowWithStruct(t, terraformOptions)
+	assert.Empty(t, idempotencyPlan.ResourceChangesMap, "A second consecutive plan should report no resource changes")
+	assertNoPolicyDrift(t, idempotencyPlan)
+
+	t.Log("✓ Idempotency verified (no drift on re-plan)")
+	endPhase1()
+
+	// Read the effective prefix back from the module's own output and require
+	// it to echo uniquePrefix verbatim; a module that sanitized or truncated
+	// name_prefix before applying it to resource names fails here.
+	effectivePrefix := terraform.Output(t, terraformOptions, "name_prefix")
+	require.Equal(t, uniquePrefix, effectivePrefix, "name_prefix output should echo back what was configured")
+
 	// ============================================================================
 	// Phase 2: Comprehensive Resource Validation
 	// ============================================================================
@@ -128,6 +324,8 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	t.Log("Phase 2: Resource Validation")
 	t.Log("========================================")
 
+	endPhase2 := report.startPhase(t, "Phase 2: Resource Validation")
+
 	// ============================================================================
 	// Validate SQS Queues
 	// ============================================================================
@@ -145,8 +343,8 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	require.NotEmpty(t, queueARN, "Queue ARN should not be empty")
 	require.NotEmpty(t, dlqARN, "DLQ ARN should not be empty")
 
-	assert.Contains(t, queueURL, uniquePrefix+"-purchase-intents", "Queue URL should contain expected queue name")
-	assert.Contains(t, dlqURL, uniquePrefix+"-purchase-intents-dlq", "DLQ URL should contain expected queue name")
+	assert.Contains(t, queueURL, effectivePrefix+"-purchase-intents", "Queue URL should contain expected queue name")
+	assert.Contains(t, dlqURL, effectivePrefix+"-purchase-intents-dlq", "DLQ URL should contain expected queue name")
 
 	t.Log("✓ SQS queues validated")
 
@@ -158,7 +356,7 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 
 	snsTopicARN := terraform.Output(t, terraformOptions, "sns_topic_arn")
 	require.NotEmpty(t, snsTopicARN, "SNS topic ARN should not be empty")
-	assert.Contains(t, snsTopicARN, uniquePrefix+"-notifications", "SNS topic ARN should contain expected topic name")
+	assert.Contains(t, snsTopicARN, effectivePrefix+"-notifications", "SNS topic ARN should contain expected topic name")
 
 	t.Log("✓ SNS topic validated")
 
@@ -178,8 +376,8 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	require.NotEmpty(t, schedulerLambdaARN, "Scheduler Lambda ARN should not be empty")
 	require.NotEmpty(t, purchaserLambdaARN, "Purchaser Lambda ARN should not be empty")
 
-	assert.Contains(t, schedulerLambdaName, uniquePrefix+"-scheduler", "Scheduler Lambda name should contain expected function name")
-	assert.Contains(t, purchaserLambdaName, uniquePrefix+"-purchaser", "Purchaser Lambda name should contain expected function name")
+	assert.Contains(t, schedulerLambdaName, effectivePrefix+"-scheduler", "Scheduler Lambda name should contain expected function name")
+	assert.Contains(t, purchaserLambdaName, effectivePrefix+"-purchaser", "Purchaser Lambda name should contain expected function name")
 
 	// Validate Lambda function configuration
 	lambdaClient := terratest_aws.NewLambdaClient(t, awsRegion)
@@ -208,49 +406,119 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	require.NotEmpty(t, schedulerRoleARN, "Scheduler Lambda role ARN should not be empty")
 	require.NotEmpty(t, purchaserRoleARN, "Purchaser Lambda role ARN should not be empty")
 
-	assert.Contains(t, schedulerRoleARN, uniquePrefix+"-scheduler", "Scheduler role ARN should contain expected role name")
-	assert.Contains(t, purchaserRoleARN, uniquePrefix+"-purchaser", "Purchaser role ARN should contain expected role name")
+	assert.Contains(t, schedulerRoleARN, effectivePrefix+"-scheduler", "Scheduler role ARN should contain expected role name")
+	assert.Contains(t, purchaserRoleARN, effectivePrefix+"-purchaser", "Purchaser role ARN should contain expected role name")
 
 	t.Log("✓ IAM roles validated")
 
 	// ============================================================================
-	// Validate EventBridge Rules
+	// Validate Scheduling Resources (CloudWatch Events or EventBridge Scheduler)
 	// ============================================================================
 
-	t.Log("Validating EventBridge rules...")
-
-	schedulerRuleName := terraform.Output(t, terraformOptions, "scheduler_rule_name")
-	purchaserRuleName := terraform.Output(t, terraformOptions, "purchaser_rule_name")
-	schedulerRuleARN := terraform.Output(t, terraformOptions, "scheduler_rule_arn")
-	purchaserRuleARN := terraform.Output(t, terraformOptions, "purchaser_rule_arn")
-
-	require.NotEmpty(t, schedulerRuleName, "Scheduler EventBridge rule name should not be empty")
-	require.NotEmpty(t, purchaserRuleName, "Purchaser EventBridge rule name should not be empty")
-	require.NotEmpty(t, schedulerRuleARN, "Scheduler rule ARN should not be empty")
-	require.NotEmpty(t, purchaserRuleARN, "Purchaser rule ARN should not be empty")
-
-	assert.Contains(t, schedulerRuleName, uniquePrefix+"-scheduler", "Scheduler rule name should contain expected rule name")
-	assert.Contains(t, purchaserRuleName, uniquePrefix+"-purchaser", "Purchaser rule name should contain expected rule name")
+	var schedulerRuleName, purchaserRuleName string
 
-	// Validate EventBridge rule details
-	sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
-	require.NoError(t, err, "Failed to create AWS session")
-
-	eventsClient := cloudwatchevents.New(sess)
-
-	schedulerRuleOutput, err := eventsClient.DescribeRule(&cloudwatchevents.DescribeRuleInput{
-		Name: aws.String(schedulerRuleName),
-	})
-	require.NoError(t, err, "Failed to describe Scheduler EventBridge rule")
-	assert.Equal(t, "ENABLED", *schedulerRuleOutput.State, "Scheduler rule should be ENABLED")
-
-	purchaserRuleOutput, err := eventsClient.DescribeRule(&cloudwatchevents.DescribeRuleInput{
-		Name: aws.String(purchaserRuleName),
-	})
-	require.NoError(t, err, "Failed to describe Purchaser EventBridge rule")
-	assert.Equal(t, "ENABLED", *purchaserRuleOutput.State, "Purchaser rule should be ENABLED")
-
-	t.Log("✓ EventBridge rules validated")
+	if schedulerEngine == "cloudwatch_events" {
+		t.Log("Validating EventBridge (CloudWatch Events) rules...")
+
+		schedulerRuleName = terraform.Output(t, terraformOptions, "scheduler_rule_name")
+		purchaserRuleName = terraform.Output(t, terraformOptions, "purchaser_rule_name")
+		schedulerRuleARN := terraform.Output(t, terraformOptions, "scheduler_rule_arn")
+		purchaserRuleARN := terraform.Output(t, terraformOptions, "purchaser_rule_arn")
+
+		require.NotEmpty(t, schedulerRuleName, "Scheduler EventBridge rule name should not be empty")
+		require.NotEmpty(t, purchaserRuleName, "Purchaser EventBridge rule name should not be empty")
+		require.NotEmpty(t, schedulerRuleARN, "Scheduler rule ARN should not be empty")
+		require.NotEmpty(t, purchaserRuleARN, "Purchaser rule ARN should not be empty")
+
+		assert.Contains(t, schedulerRuleName, effectivePrefix+"-scheduler", "Scheduler rule name should contain expected rule name")
+		assert.Contains(t, purchaserRuleName, effectivePrefix+"-purchaser", "Purchaser rule name should contain expected rule name")
+
+		// Validate EventBridge rule details
+		sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+		require.NoError(t, err, "Failed to create AWS session")
+
+		eventsClient := cloudwatchevents.New(sess)
+
+		schedulerRuleOutput, err := eventsClient.DescribeRule(&cloudwatchevents.DescribeRuleInput{
+			Name: aws.String(schedulerRuleName),
+		})
+		require.NoError(t, err, "Failed to describe Scheduler EventBridge rule")
+		assert.Equal(t, "ENABLED", *schedulerRuleOutput.State, "Scheduler rule should be ENABLED")
+
+		purchaserRuleOutput, err := eventsClient.DescribeRule(&cloudwatchevents.DescribeRuleInput{
+			Name: aws.String(purchaserRuleName),
+		})
+		require.NoError(t, err, "Failed to describe Purchaser EventBridge rule")
+		assert.Equal(t, "ENABLED", *purchaserRuleOutput.State, "Purchaser rule should be ENABLED")
+
+		t.Log("✓ EventBridge rules validated")
+	} else {
+		t.Log("Validating EventBridge Scheduler schedules...")
+
+		scheduleGroupName := terraform.Output(t, terraformOptions, "scheduler_schedule_group_name")
+		scheduleGroupARN := terraform.Output(t, terraformOptions, "scheduler_schedule_group_arn")
+		schedulerScheduleARN := terraform.Output(t, terraformOptions, "scheduler_schedule_arn")
+		purchaserScheduleARN := terraform.Output(t, terraformOptions, "purchaser_schedule_arn")
+
+		require.NotEmpty(t, scheduleGroupName, "Scheduler schedule group name should not be empty")
+		require.NotEmpty(t, scheduleGroupARN, "Scheduler schedule group ARN should not be empty")
+		require.NotEmpty(t, schedulerScheduleARN, "Scheduler schedule ARN should not be empty")
+		require.NotEmpty(t, purchaserScheduleARN, "Purchaser schedule ARN should not be empty")
+
+		assert.Contains(t, scheduleGroupName, effectivePrefix, "Schedule group name should contain expected prefix")
+
+		sess, err := terratest_aws.NewAuthenticatedSession(awsRegion)
+		require.NoError(t, err, "Failed to create AWS session")
+
+		schedulerClient := scheduler.New(sess)
+
+		groupOutput, err := schedulerClient.GetScheduleGroup(&scheduler.GetScheduleGroupInput{
+			Name: aws.String(scheduleGroupName),
+		})
+		require.NoError(t, err, "Failed to get schedule group")
+		assert.Equal(t, "ACTIVE", *groupOutput.State, "Schedule group should be ACTIVE")
+
+		schedulerScheduleName := terraform.Output(t, terraformOptions, "scheduler_rule_name")
+		purchaserScheduleName := terraform.Output(t, terraformOptions, "purchaser_rule_name")
+		schedulerRuleName = schedulerScheduleName
+		purchaserRuleName = purchaserScheduleName
+
+		schedulerScheduleOutput, err := schedulerClient.GetSchedule(&scheduler.GetScheduleInput{
+			Name:      aws.String(schedulerScheduleName),
+			GroupName: aws.String(scheduleGroupName),
+		})
+		require.NoError(t, err, "Failed to get Scheduler schedule")
+		assert.Equal(t, "ENABLED", *schedulerScheduleOutput.State, "Scheduler schedule should be ENABLED")
+
+		purchaserScheduleOutput, err := schedulerClient.GetSchedule(&scheduler.GetScheduleInput{
+			Name:      aws.String(purchaserScheduleName),
+			GroupName: aws.String(scheduleGroupName),
+		})
+		require.NoError(t, err, "Failed to get Purchaser schedule")
+		assert.Equal(t, "ENABLED", *purchaserScheduleOutput.State, "Purchaser schedule should be ENABLED")
+
+		t.Log("✓ EventBridge Scheduler schedules validated")
+
+		// ============================================================================
+		// Phase 5: Schedule Group Membership - No Orphan Schedules
+		// ============================================================================
+
+		t.Log("Listing schedules within the group to check for orphans...")
+
+		listOutput, err := schedulerClient.ListSchedules(&scheduler.ListSchedulesInput{
+			GroupName: aws.String(scheduleGroupName),
+		})
+		require.NoError(t, err, "Failed to list schedules in group %s", scheduleGroupName)
+
+		var scheduleNamesInGroup []string
+		for _, summary := range listOutput.Schedules {
+			scheduleNamesInGroup = append(scheduleNamesInGroup, aws.StringValue(summary.Name))
+		}
+		assert.ElementsMatch(t, []string{schedulerScheduleName, purchaserScheduleName}, scheduleNamesInGroup,
+			"Schedule group should contain exactly the Scheduler and Purchaser schedules, no orphans from other deployments")
+
+		t.Log("✓ No orphan schedules found in this deployment's group")
+	}
 
 	// ============================================================================
 	// Validate CloudWatch Alarms
@@ -284,6 +552,7 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	assert.Equal(t, "true", moduleConfig["dry_run"], "Dry run should be enabled")
 
 	t.Log("✓ Module configuration validated")
+	endPhase2()
 
 	// ============================================================================
 	// Phase 3: End-to-End Functional Testing
@@ -293,6 +562,8 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	t.Log("Phase 3: Functional Testing")
 	t.Log("========================================")
 
+	endPhase3 := report.startPhase(t, "Phase 3: Functional Testing")
+
 	// Get initial queue state
 	sqsClient := terratest_aws.NewSqsClient(t, awsRegion)
 
@@ -353,6 +624,32 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	assert.Equal(t, initialMessageCount, finalMessageCount, "In dry-run mode, no new messages should be queued to SQS")
 
 	t.Log("✓ Dry-run mode verified (no side effects)")
+	endPhase3()
+
+	// ============================================================================
+	// Phase 3b: Failure Injection
+	// ============================================================================
+
+	t.Log("========================================")
+	t.Log("Phase 3b: Failure Injection")
+	t.Log("========================================")
+
+	endPhase3b := report.startPhase(t, "Phase 3b: Failure Injection")
+
+	runFailureInjectionPhase(t, failureInjectionInput{
+		awsRegion:              awsRegion,
+		queueURL:               queueURL,
+		dlqURL:                 dlqURL,
+		snsTopicARN:            snsTopicARN,
+		schedulerLambdaName:    schedulerLambdaName,
+		schedulerErrorAlarmARN: schedulerErrorAlarmARN,
+		dlqAlarmARN:            dlqAlarmARN,
+		lambdaClient:           lambdaClient,
+		sqsClient:              sqsClient,
+	})
+
+	t.Log("✓ Failure paths validated (DLQ and error alarms wired correctly)")
+	endPhase3b()
 
 	// ============================================================================
 	// Phase 4: Cleanup Validation
@@ -362,32 +659,38 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	t.Log("Phase 4: Cleanup Validation")
 	t.Log("========================================")
 
+	endPhase4 := report.startPhase(t, "Phase 4: Cleanup Validation")
+
 	// The defer statement will handle cleanup automatically
 	// Validate that we have all resource identifiers needed for cleanup
 	t.Log("Verifying all resource identifiers are available for cleanup...")
 
 	resourceIdentifiers := map[string]string{
-		"Queue URL":                   queueURL,
-		"DLQ URL":                     dlqURL,
-		"SNS Topic ARN":               snsTopicARN,
-		"Scheduler Lambda Name":       schedulerLambdaName,
-		"Purchaser Lambda Name":       purchaserLambdaName,
-		"Scheduler Role ARN":          schedulerRoleARN,
-		"Purchaser Role ARN":          purchaserRoleARN,
-		"Scheduler EventBridge Rule":  schedulerRuleName,
-		"Purchaser EventBridge Rule":  purchaserRuleName,
-		"Scheduler Error Alarm ARN":   schedulerErrorAlarmARN,
-		"Purchaser Error Alarm ARN":   purchaserErrorAlarmARN,
-		"Reporter Error Alarm ARN":    reporterErrorAlarmARN,
-		"DLQ Alarm ARN":               dlqAlarmARN,
+		"Queue URL":                  queueURL,
+		"DLQ URL":                    dlqURL,
+		"SNS Topic ARN":              snsTopicARN,
+		"Scheduler Lambda Name":      schedulerLambdaName,
+		"Purchaser Lambda Name":      purchaserLambdaName,
+		"Scheduler Role ARN":         schedulerRoleARN,
+		"Purchaser Role ARN":         purchaserRoleARN,
+		"Scheduler EventBridge Rule": schedulerRuleName,
+		"Purchaser EventBridge Rule": purchaserRuleName,
+		"Scheduler Error Alarm ARN":  schedulerErrorAlarmARN,
+		"Purchaser Error Alarm ARN":  purchaserErrorAlarmARN,
+		"Reporter Error Alarm ARN":   reporterErrorAlarmARN,
+		"DLQ Alarm ARN":              dlqAlarmARN,
 	}
 
 	for name, identifier := range resourceIdentifiers {
 		assert.NotEmpty(t, identifier, "%s should not be empty for cleanup", name)
 	}
 
+	report.ResourceIdentifiers = resourceIdentifiers
+
 	t.Log("✓ All resource identifiers validated for cleanup")
 
+	endPhase4()
+
 	t.Log("========================================")
 	t.Log("Test Complete - Cleanup Will Run via defer")
 	t.Log("========================================")
@@ -395,3 +698,295 @@ func TestFullDeploymentAndCleanup(t *testing.T) {
 	// Note: terraform.Destroy() will be called automatically via defer
 	// when this function exits, ensuring all AWS resources are cleaned up
 }
+
+// failureInjectionInput bundles the resources Phase 3b needs to exercise the
+// module's monitoring wiring end-to-end rather than just asserting ARNs exist.
+type failureInjectionInput struct {
+	awsRegion              string
+	queueURL               string
+	dlqURL                 string
+	snsTopicARN            string
+	schedulerLambdaName    string
+	schedulerErrorAlarmARN string
+	dlqAlarmARN            string
+	lambdaClient           *lambda.Lambda
+	sqsClient              *sqs.SQS
+}
+
+const failureInjectionPollTimeout = 3 * time.Minute
+const failureInjectionPollInterval = 10 * time.Second
+
+// runFailureInjectionPhase invokes the Scheduler Lambda with a malformed
+// payload and pushes a poison message onto the main queue, then confirms the
+// error alarm and DLQ alarm both transition to ALARM and that the DLQ alarm's
+// SNS notification is observable through a test-only subscription.
+func runFailureInjectionPhase(t *testing.T, in failureInjectionInput) {
+	sess, err := terratest_aws.NewAuthenticatedSession(in.awsRegion)
+	require.NoError(t, err, "Failed to create AWS session for failure injection")
+
+	cwClient := cloudwatch.New(sess)
+	snsClient := sns.New(sess)
+
+	// Set up a test-only SQS subscription on the SNS topic so we can capture
+	// the alarm notification body without relying on the configured email
+	// endpoints (which can't be polled programmatically).
+	captureQueueURL, captureQueueARN := createAlarmCaptureQueue(t, in.sqsClient, in.snsTopicARN)
+	defer deleteAlarmCaptureQueue(t, in.sqsClient, captureQueueURL)
+
+	subscribeOutput, err := snsClient.Subscribe(&sns.SubscribeInput{
+		TopicArn: aws.String(in.snsTopicARN),
+		Protocol: aws.String("sqs"),
+		Endpoint: aws.String(captureQueueARN),
+	})
+	require.NoError(t, err, "Failed to subscribe capture queue to SNS topic")
+	defer snsClient.Unsubscribe(&sns.UnsubscribeInput{SubscriptionArn: subscribeOutput.SubscriptionArn})
+
+	t.Log("Invoking Scheduler Lambda with a forced-error payload...")
+
+	invokeResult, err := in.lambdaClient.Invoke(&lambda.InvokeInput{
+		FunctionName: aws.String(in.schedulerLambdaName),
+		Payload:      []byte(`{"__force_error__": true}`),
+	})
+	require.NoError(t, err, "Failed to invoke Scheduler Lambda with forced-error payload")
+	assert.NotNil(t, invokeResult.FunctionError, "Forced-error invocation should report a function error when dry_run is true")
+
+	t.Log("Waiting for scheduler error alarm to transition to ALARM...")
+	waitForAlarmState(t, cwClient, in.schedulerErrorAlarmARN, "ALARM", failureInjectionPollTimeout, failureInjectionPollInterval)
+
+	t.Log("Sending a poison message to the main queue to exhaust maxReceiveCount...")
+
+	_, err = in.sqsClient.SendMessage(&sqs.SendMessageInput{
+		QueueUrl:    aws.String(in.queueURL),
+		MessageBody: aws.String(`{"__force_error__": true}`),
+		MessageAttributes: map[string]*sqs.MessageAttributeValue{
+			"ForcePurchaserError": {
+				DataType:    aws.String("String"),
+				StringValue: aws.String("true"),
+			},
+		},
+	})
+	require.NoError(t, err, "Failed to send poison message to main queue")
+
+	t.Log("Waiting for poison message to land on the DLQ...")
+	require.Eventually(t, func() bool {
+		attrs, err := in.sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl:       aws.String(in.dlqURL),
+			AttributeNames: []*string{aws.String("ApproximateNumberOfMessages")},
+		})
+		if err != nil {
+			return false
+		}
+		count, err := strconv.Atoi(*attrs.Attributes["ApproximateNumberOfMessages"])
+		return err == nil && count > 0
+	}, failureInjectionPollTimeout, failureInjectionPollInterval, "Poison message never landed on the DLQ")
+
+	t.Log("Waiting for DLQ alarm to transition to ALARM...")
+	waitForAlarmState(t, cwClient, in.dlqAlarmARN, "ALARM", failureInjectionPollTimeout, failureInjectionPollInterval)
+
+	t.Log("Verifying the DLQ alarm notification was delivered via SNS...")
+	require.Eventually(t, func() bool {
+		received, err := in.sqsClient.ReceiveMessage(&sqs.ReceiveMessageInput{
+			QueueUrl:            aws.String(captureQueueURL),
+			MaxNumberOfMessages: aws.Int64(5),
+			WaitTimeSeconds:     aws.Int64(5),
+		})
+		if err != nil || len(received.Messages) == 0 {
+			return false
+		}
+		for _, msg := range received.Messages {
+			if strings.Contains(*msg.Body, in.dlqAlarmARN) {
+				return true
+			}
+		}
+		return false
+	}, failureInjectionPollTimeout, failureInjectionPollInterval, "Did not observe a DLQ alarm notification referencing its own ARN")
+}
+
+// waitForAlarmState polls DescribeAlarms until the given alarm reports
+// wantState or the timeout elapses.
+func waitForAlarmState(t *testing.T, cwClient *cloudwatch.CloudWatch, alarmARN, wantState string, timeout, interval time.Duration) {
+	require.Eventually(t, func() bool {
+		describeOutput, err := cwClient.DescribeAlarms(&cloudwatch.DescribeAlarmsInput{})
+		if err != nil {
+			return false
+		}
+		for _, alarm := range describeOutput.MetricAlarms {
+			if alarm.AlarmArn != nil && *alarm.AlarmArn == alarmARN {
+				return alarm.StateValue != nil && *alarm.StateValue == wantState
+			}
+		}
+		return false
+	}, timeout, interval, "Alarm %s never transitioned to %s", alarmARN, wantState)
+}
+
+// createAlarmCaptureQueue provisions a short-lived SQS queue (with a policy
+// allowing the given SNS topic to send to it) used only to observe alarm
+// notifications during the failure-injection phase.
+func createAlarmCaptureQueue(t *testing.T, sqsClient *sqs.SQS, snsTopicARN string) (queueURL, queueARN string) {
+	queueName := fmt.Sprintf("sp-autopilot-alarm-capture-%d", time.Now().UnixNano())
+
+	createOutput, err := sqsClient.CreateQueue(&sqs.CreateQueueInput{
+		QueueName: aws.String(queueName),
+	})
+	require.NoError(t, err, "Failed to create alarm capture queue")
+	queueURL = *createOutput.QueueUrl
+
+	attrs, err := sqsClient.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+		QueueUrl:       aws.String(queueURL),
+		AttributeNames: []*string{aws.String("QueueArn")},
+	})
+	require.NoError(t, err, "Failed to get alarm capture queue ARN")
+	queueARN = *attrs.Attributes["QueueArn"]
+
+	policy := fmt.Sprintf(`{
+		"Version": "2012-10-17",
+		"Statement": [{
+			"Effect": "Allow",
+			"Principal": {"Service": "sns.amazonaws.com"},
+			"Action": "sqs:SendMessage",
+			"Resource": "%s",
+			"Condition": {"ArnEquals": {"aws:SourceArn": "%s"}}
+		}]
+	}`, queueARN, snsTopicARN)
+
+	_, err = sqsClient.SetQueueAttributes(&sqs.SetQueueAttributesInput{
+		QueueUrl: aws.String(queueURL),
+		Attributes: map[string]*string{
+			"Policy": aws.String(policy),
+		},
+	})
+	require.NoError(t, err, "Failed to set alarm capture queue policy")
+
+	return queueURL, queueARN
+}
+
+// deleteAlarmCaptureQueue removes the short-lived alarm capture queue.
+func deleteAlarmCaptureQueue(t *testing.T, sqsClient *sqs.SQS, queueURL string) {
+	_, err := sqsClient.DeleteQueue(&sqs.DeleteQueueInput{QueueUrl: aws.String(queueURL)})
+	if err != nil {
+		t.Logf("Warning: failed to delete alarm capture queue %s: %v", queueURL, err)
+	}
+}
+
+// assertPlanInvariants walks the planned-changes JSON produced by
+// `terraform plan` and asserts invariants that should hold before any
+// resource is actually created, mirroring how terraform-core's internal
+// plan tests assert against plan.Changes.Resources directly.
+func assertPlanInvariants(t *testing.T, plan *terraform.PlanStruct, schedulerEngine string) {
+	lambdaCreations := 0
+	queueCount := 0
+	dlqFound := false
+
+	for address, change := range plan.ResourceChangesMap {
+		switch change.Type {
+		case "aws_lambda_function":
+			if changeCreates(change) {
+				lambdaCreations++
+			}
+		case "aws_sqs_queue":
+			queueCount++
+			if strings.Contains(address, "dlq") {
+				dlqFound = true
+			}
+		case "aws_iam_policy", "aws_iam_role_policy":
+			assertNoWildcardIAMStatement(t, address, change)
+		}
+	}
+
+	// The fixture vars used by this test configure the reporter (schedule and
+	// error alarm), so the scheduler, purchaser and reporter Lambdas are all
+	// expected to be created.
+	assert.Equal(t, 3, lambdaCreations, "Plan should create exactly three Lambda functions (scheduler, purchaser, reporter) when reporter is enabled")
+	assert.Equal(t, 2, queueCount, "Plan should create both the main SQS queue and the DLQ")
+	// Only the queue's address is checked here; the main queue's redrive
+	// policy is not inspected.
+	assert.True(t, dlqFound, "Plan should include an aws_sqs_queue whose address contains \"dlq\"")
+
+	t.Logf("Plan invariants validated for scheduler engine %q", schedulerEngine)
+}
+
+// assertNoPolicyDrift fails the test with a specific, policy-attributed
+// message if a second consecutive plan wants to update any inline JSON
+// policy document (SNS topic policy, SQS queue policy, or a Scheduler/
+// Purchaser IAM role policy). These are the resources most prone to
+// spurious diffs from AWS reordering policy document keys server-side, so
+// they get a pointed assertion on top of the blanket
+// ResourceChangesMap-is-empty check above.
+func assertNoPolicyDrift(t *testing.T, plan *terraform.PlanStruct) {
+	for address, change := range plan.ResourceChangesMap {
+		switch change.Type {
+		case "aws_sns_topic", "aws_sqs_queue", "aws_iam_role_policy":
+			assert.False(t, changeUpdates(change), "%s (%s) should produce no in-place update on re-plan; its policy document is drifting", address, change.Type)
+		}
+	}
+}
+
+// changeUpdates reports whether a resource change plans an in-place update.
+func changeUpdates(change *tfjson.ResourceChange) bool {
+	for _, action := range change.Change.Actions {
+		if action == tfjson.ActionUpdate {
+			return true
+		}
+	}
+	return false
+}
+
+// changeCreates reports whether a resource change plans a create action.
+func changeCreates(change *tfjson.ResourceChange) bool {
+	for _, action := range change.Change.Actions {
+		if action == tfjson.ActionCreate {
+			return true
+		}
+	}
+	return false
+}
+
+// assertNoWildcardIAMStatement fails the test if a planned IAM policy change
+// grants a wildcard Action or Resource. No statement is exempt: there is no
+// allowlist.
+func assertNoWildcardIAMStatement(t *testing.T, address string, change *tfjson.ResourceChange) {
+	after, ok := change.Change.After.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	policyJSON, ok := after["policy"].(string)
+	if !ok || policyJSON == "" {
+		return
+	}
+
+	var policyDoc map[string]interface{}
+	if err := json.Unmarshal([]byte(policyJSON), &policyDoc); err != nil {
+		return
+	}
+
+	statements, ok := policyDoc["Statement"].([]interface{})
+	if !ok {
+		return
+	}
+
+	for _, raw := range statements {
+		statement, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		assert.False(t, containsWildcard(statement["Action"]), "%s should not grant a wildcard Action", address)
+		assert.False(t, containsWildcard(statement["Resource"]), "%s should not grant a wildcard Resource", address)
+	}
+}
+
+// containsWildcard reports whether an Action/Resource policy field (string
+// or []interface{}) contains the literal "*".
+func containsWildcard(field interface{}) bool {
+	switch v := field.(type) {
+	case string:
+		return v == "*"
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && s == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
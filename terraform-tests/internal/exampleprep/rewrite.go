@@ -0,0 +1,230 @@
+// Package exampleprep rewrites a copy of an example's .tf files so integration
+// tests can exercise the local module source instead of the registry, without
+// the fragility of line-oriented string replacement: edits are made on the
+// parsed HCL syntax tree, so reformatting, provider aliases, or multi-line
+// attribute assignments don't break the rewrite.
+package exampleprep
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// registrySource is the module source address examples declare in the
+// registry, which RewriteExampleForLocalSource points at a local path instead.
+const registrySource = "etiennechabert/sp-autopilot/aws"
+
+// RewriteOptions configures RewriteExampleForLocalSource.
+type RewriteOptions struct {
+	// LocalSourcePath replaces any module "source" attribute that points at
+	// registrySource. It's used verbatim, so it must already be relative to
+	// destDir (e.g. "../../../../").
+	LocalSourcePath string
+
+	// DefaultTags is merged into every provider "aws" block's default_tags.tags,
+	// without overwriting any key the example already sets.
+	DefaultTags map[string]string
+}
+
+// RewriteExampleForLocalSource copies every .tf file under srcDir into
+// destDir, rewriting module blocks that source from the registry to use
+// opts.LocalSourcePath (dropping their version constraint, which only applies
+// to registry sources) and merging opts.DefaultTags into each provider "aws"
+// block's default_tags. Non-.tf files are left alone.
+func RewriteExampleForLocalSource(srcDir, destDir string, opts RewriteOptions) error {
+	if err := os.MkdirAll(destDir, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", destDir, err)
+	}
+
+	return filepath.Walk(srcDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !strings.HasSuffix(path, ".tf") {
+			return nil
+		}
+
+		rewritten, err := rewriteFile(path, opts)
+		if err != nil {
+			return fmt.Errorf("failed to rewrite %s: %w", path, err)
+		}
+
+		destPath := filepath.Join(destDir, filepath.Base(path))
+		if err := os.WriteFile(destPath, rewritten, 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", destPath, err)
+		}
+		return nil
+	})
+}
+
+// rewriteFile parses a single .tf file and returns its rewritten contents.
+func rewriteFile(path string, opts RewriteOptions) ([]byte, error) {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, diags := hclwrite.ParseConfig(src, path, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	for _, block := range file.Body().Blocks() {
+		switch {
+		case block.Type() == "module":
+			rewriteModuleSource(block, opts.LocalSourcePath)
+		case block.Type() == "provider" && len(block.Labels()) == 1 && block.Labels()[0] == "aws":
+			mergeDefaultTags(block, opts.DefaultTags)
+		}
+	}
+
+	return file.Bytes(), nil
+}
+
+// rewriteModuleSource points block's source attribute at localSourcePath and
+// drops its version constraint, but only if source currently points at the
+// registry - a module block sourced elsewhere (e.g. a local path already, or
+// a different registry module) is left untouched.
+func rewriteModuleSource(block *hclwrite.Block, localSourcePath string) {
+	attr := block.Body().GetAttribute("source")
+	if attr == nil || !isRegistrySource(attr) {
+		return
+	}
+
+	block.Body().SetAttributeValue("source", cty.StringVal(localSourcePath))
+	block.Body().RemoveAttribute("version")
+}
+
+// isRegistrySource reports whether attr's literal value is registrySource,
+// tolerant of however its tokens happen to be spaced, wrapped in parens, or
+// split across lines (e.g. `source = (\n  "..."\n)`).
+func isRegistrySource(attr *hclwrite.Attribute) bool {
+	raw := string(attr.Expr().BuildTokens(nil).Bytes())
+	raw = strings.Join(strings.Fields(raw), "")
+	raw = strings.Trim(raw, "()")
+	raw = strings.Trim(raw, `"`)
+	return raw == registrySource
+}
+
+// mergeDefaultTags ensures block (a provider "aws" block) has a default_tags
+// block whose tags include every key in tags, without overwriting a key the
+// example already sets.
+func mergeDefaultTags(block *hclwrite.Block, tags map[string]string) {
+	if len(tags) == 0 {
+		return
+	}
+
+	defaultTags := block.Body().FirstMatchingBlock("default_tags", nil)
+	if defaultTags == nil {
+		defaultTags = block.Body().AppendNewBlock("default_tags", nil)
+	}
+
+	mergeTagsAttribute(defaultTags.Body(), tags)
+}
+
+// mergeTagsAttribute adds any of tags not already present in body's "tags"
+// attribute, preserving every existing entry (literal or expression) as-is.
+func mergeTagsAttribute(body *hclwrite.Body, tags map[string]string) {
+	attr := body.GetAttribute("tags")
+	if attr == nil {
+		body.SetAttributeRaw("tags", newTagsObjectTokens(tags))
+		return
+	}
+
+	tokens := attr.Expr().BuildTokens(nil)
+	existing := string(tokens.Bytes())
+
+	var missing []string
+	for key := range tags {
+		if !strings.Contains(existing, key) {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	sort.Strings(missing)
+
+	insertAt := lastCloseBraceIndex(tokens)
+	if insertAt < 0 {
+		// Not a plain object literal (e.g. a merge() call or a variable
+		// reference) - safest to leave the user's expression untouched
+		// rather than guess where to splice new keys in.
+		return
+	}
+
+	var insertion hclwrite.Tokens
+	for _, key := range missing {
+		insertion = append(insertion, tagTokens(key, tags[key])...)
+	}
+
+	merged := make(hclwrite.Tokens, 0, len(tokens)+len(insertion))
+	merged = append(merged, tokens[:insertAt]...)
+	merged = append(merged, insertion...)
+	merged = append(merged, tokens[insertAt:]...)
+
+	body.SetAttributeRaw("tags", merged)
+}
+
+// lastCloseBraceIndex returns the index of the final top-level "}" token in
+// tokens, or -1 if tokens isn't a brace-delimited object literal.
+func lastCloseBraceIndex(tokens hclwrite.Tokens) int {
+	for i := len(tokens) - 1; i >= 0; i-- {
+		if tokens[i].Type == hclsyntax.TokenCBrace {
+			return i
+		}
+	}
+	return -1
+}
+
+// newTagsObjectTokens renders a brand-new single-line `{ key = "value", ... }`
+// object literal for tags. Building it on one line (rather than reusing the
+// per-line tagTokens the splice-into-existing path uses) sidesteps
+// hclwrite's column-alignment pass entirely, so every key gets the same
+// single-space formatting regardless of key length.
+func newTagsObjectTokens(tags map[string]string) hclwrite.Tokens {
+	keys := make([]string, 0, len(tags))
+	for key := range tags {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tokens := hclwrite.Tokens{
+		{Type: hclsyntax.TokenOBrace, Bytes: []byte("{")},
+	}
+	for i, key := range keys {
+		if i > 0 {
+			tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenComma, Bytes: []byte(",")})
+		}
+		tokens = append(tokens,
+			&hclwrite.Token{Type: hclsyntax.TokenIdent, Bytes: []byte(key)},
+			&hclwrite.Token{Type: hclsyntax.TokenEqual, Bytes: []byte("=")},
+			&hclwrite.Token{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`)},
+			&hclwrite.Token{Type: hclsyntax.TokenQuotedLit, Bytes: []byte(tags[key])},
+			&hclwrite.Token{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)},
+		)
+	}
+	tokens = append(tokens, &hclwrite.Token{Type: hclsyntax.TokenCBrace, Bytes: []byte("}")})
+	return tokens
+}
+
+// tagTokens renders `<newline>key = "value"` as raw tokens, suitable for
+// splicing into an existing object literal's token stream.
+func tagTokens(key, value string) hclwrite.Tokens {
+	return hclwrite.Tokens{
+		{Type: hclsyntax.TokenNewline, Bytes: []byte("\n")},
+		{Type: hclsyntax.TokenIdent, Bytes: []byte(key)},
+		{Type: hclsyntax.TokenEqual, Bytes: []byte("="), SpacesBefore: 1},
+		{Type: hclsyntax.TokenOQuote, Bytes: []byte(`"`), SpacesBefore: 1},
+		{Type: hclsyntax.TokenQuotedLit, Bytes: []byte(value)},
+		{Type: hclsyntax.TokenCQuote, Bytes: []byte(`"`)},
+	}
+}
@@ -0,0 +1,141 @@
+package exampleprep
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rewriteSingleFile(t *testing.T, source string, opts RewriteOptions) string {
+	t.Helper()
+
+	srcDir := t.TempDir()
+	destDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "main.tf"), []byte(source), 0644))
+
+	require.NoError(t, RewriteExampleForLocalSource(srcDir, destDir, opts))
+
+	out, err := os.ReadFile(filepath.Join(destDir, "main.tf"))
+	require.NoError(t, err)
+	return string(out)
+}
+
+func TestRewriteModuleSource_RegistryToLocal(t *testing.T) {
+	source := `module "sp_autopilot" {
+  source  = "etiennechabert/sp-autopilot/aws"
+  version = "~> 1.0"
+
+  name_prefix = "example"
+}
+`
+	got := rewriteSingleFile(t, source, RewriteOptions{LocalSourcePath: "../../../../"})
+
+	assert.Contains(t, got, `source = "../../../../"`)
+	assert.NotContains(t, got, "etiennechabert/sp-autopilot/aws")
+	assert.NotContains(t, got, "version")
+}
+
+func TestRewriteModuleSource_MultiLineSourceAssignment(t *testing.T) {
+	source := `module "sp_autopilot" {
+  source = (
+    "etiennechabert/sp-autopilot/aws"
+  )
+  version = ">= 1.0, < 2.0"
+}
+`
+	got := rewriteSingleFile(t, source, RewriteOptions{LocalSourcePath: "../../../../"})
+
+	assert.Contains(t, got, `source = "../../../../"`)
+	assert.NotContains(t, got, "etiennechabert/sp-autopilot/aws")
+}
+
+func TestRewriteModuleSource_NonRegistrySourceUnaffected(t *testing.T) {
+	source := `module "other" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 5.0"
+}
+`
+	got := rewriteSingleFile(t, source, RewriteOptions{LocalSourcePath: "../../../../"})
+
+	assert.Contains(t, got, `source  = "terraform-aws-modules/vpc/aws"`)
+	assert.Contains(t, got, `version = "~> 5.0"`)
+}
+
+func TestMergeDefaultTags_AddsBlockWhenAbsent(t *testing.T) {
+	source := `provider "aws" {
+  region = "us-east-1"
+}
+`
+	got := rewriteSingleFile(t, source, RewriteOptions{
+		DefaultTags: map[string]string{"Environment": "test", "ManagedBy": "terratest"},
+	})
+
+	assert.Contains(t, got, "default_tags")
+	assert.Contains(t, got, `Environment = "test"`)
+	assert.Contains(t, got, `ManagedBy = "terratest"`)
+}
+
+func TestMergeDefaultTags_MergesWithoutOverwritingExisting(t *testing.T) {
+	source := `provider "aws" {
+  region = "us-east-1"
+
+  default_tags {
+    tags = {
+      Environment = "production"
+      Team        = "platform"
+    }
+  }
+}
+`
+	got := rewriteSingleFile(t, source, RewriteOptions{
+		DefaultTags: map[string]string{"Environment": "test", "ManagedBy": "terratest"},
+	})
+
+	// The example's own Environment value is preserved, not overwritten...
+	assert.Contains(t, got, `Environment = "production"`)
+	assert.Contains(t, got, `Team        = "platform"`)
+	// ...but the missing ManagedBy key is still merged in.
+	assert.Contains(t, got, `ManagedBy = "terratest"`)
+	// Only one default_tags block should exist.
+	assert.Equal(t, 1, countOccurrences(got, "default_tags"))
+}
+
+func TestRewriteAliasedProviders_OnlyAWSBlocksGetDefaultTags(t *testing.T) {
+	source := `provider "aws" {
+  alias  = "us_east_1"
+  region = "us-east-1"
+}
+
+provider "aws" {
+  alias  = "us_west_2"
+  region = "us-west-2"
+}
+
+provider "google" {
+  project = "example"
+}
+`
+	got := rewriteSingleFile(t, source, RewriteOptions{
+		DefaultTags: map[string]string{"Environment": "test"},
+	})
+
+	assert.Equal(t, 2, countOccurrences(got, "default_tags"))
+	assert.NotContains(t, got, `provider "google" {
+  project = "example"
+
+  default_tags`)
+}
+
+func countOccurrences(haystack, needle string) int {
+	count := 0
+	for i := 0; i+len(needle) <= len(haystack); i++ {
+		if haystack[i:i+len(needle)] == needle {
+			count++
+			i += len(needle) - 1
+		}
+	}
+	return count
+}